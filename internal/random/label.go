@@ -0,0 +1,20 @@
+// Package random provides random words used to probe how a target responds to something that does not exist
+package random
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// alphabet is used to generate labels that are very unlikely to exist on the target
+const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Label returns a random lowercase alphanumeric label of the given length, which is valid both as a subdomain and as
+// a path
+func Label(length int) string {
+	var sb strings.Builder
+	for range length {
+		sb.WriteByte(alphabet[rand.Intn(len(alphabet))]) // #nosec G404
+	}
+	return sb.String()
+}
@@ -0,0 +1,18 @@
+package random
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	t.Run("should return a lowercase alphanumeric label of the given length", func(t *testing.T) {
+		label := Label(32)
+		if len(label) != 32 {
+			t.Errorf("Expected a label of length 32, but got %d", len(label))
+		}
+		if strings.Trim(label, alphabet) != "" {
+			t.Errorf("Expected only lowercase letters and digits, but got %s", label)
+		}
+	})
+}
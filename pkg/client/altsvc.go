@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultAltSvcMaxAge is how long an alternative service is remembered for when the Alt-Svc header does not say
+const defaultAltSvcMaxAge = 24 * time.Hour
+
+// altService is where a host has advertised that it can also be reached over HTTP/3, and until when
+type altService struct {
+	address string
+	expires time.Time
+}
+
+// altSvcTransport sends requests over tcp until a host advertises HTTP/3 with an Alt-Svc header, after which requests
+// to that host are sent over HTTP/3 to the advertised address. A host that then cannot be reached over HTTP/3 is sent
+// the request over tcp again and is forgotten until it advertises HTTP/3 again.
+type altSvcTransport struct {
+	tcp http.RoundTripper
+	h3  *http3.Transport
+
+	mu       sync.RWMutex
+	services map[string]altService
+}
+
+// newAltSvcTransport returns a transport that discovers HTTP/3 from the Alt-Svc header of responses sent over tcp
+func newAltSvcTransport(config TransportConfig, tcp http.RoundTripper) *altSvcTransport {
+	t := &altSvcTransport{
+		tcp:      tcp,
+		h3:       newHTTP3Transport(config),
+		services: make(map[string]altService),
+	}
+	// the url and so the server name stay the same, only the address that is dialled changes
+	t.h3.Dial = func(ctx context.Context, addr string, tlsConf *tls.Config, quicConf *quic.Config) (*quic.Conn, error) {
+		if service, ok := t.lookup(addr, time.Now()); ok {
+			addr = service.address
+		}
+		return quic.DialAddrEarly(ctx, addr, tlsConf, quicConf)
+	}
+	return t
+}
+
+// RoundTrip will send the request over HTTP/3 if the host has advertised it, falling back to tcp otherwise
+func (t *altSvcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return t.tcp.RoundTrip(req)
+	}
+	origin := originAddr(req.URL)
+	if _, ok := t.lookup(origin, time.Now()); ok {
+		resp, err := t.h3.RoundTrip(rewind(req))
+		if err == nil {
+			return resp, nil
+		}
+		log.Debugf("HTTP/3 request to %s failed, falling back to tcp: %v", origin, err)
+		t.forget(origin)
+		req = rewind(req)
+	}
+
+	resp, err := t.tcp.RoundTrip(req)
+	if err == nil {
+		t.observe(origin, req.URL.Hostname(), resp.Header.Values("Alt-Svc"), time.Now())
+	}
+	return resp, err
+}
+
+// lookup returns the alternative service the origin has advertised, if it has not expired
+func (t *altSvcTransport) lookup(origin string, now time.Time) (altService, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	service, ok := t.services[origin]
+	return service, ok && now.Before(service.expires)
+}
+
+// observe will remember or clear the alternative service for the origin from the Alt-Svc header of its response
+func (t *altSvcTransport) observe(origin, hostname string, values []string, now time.Time) {
+	if len(values) == 0 {
+		return
+	}
+	service, cleared, ok := parseAltSvc(values, hostname, now)
+	if cleared {
+		t.forget(origin)
+		return
+	}
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, known := t.services[origin]; !known {
+		log.Debugf("%s advertised HTTP/3 at %s", origin, service.address)
+	}
+	t.services[origin] = service
+}
+
+// forget will stop sending requests for the origin over HTTP/3
+func (t *altSvcTransport) forget(origin string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.services, origin)
+}
+
+// CloseIdleConnections will close the idle connections of both transports
+func (t *altSvcTransport) CloseIdleConnections() {
+	if closer, ok := t.tcp.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+	t.h3.CloseIdleConnections()
+}
+
+// newHTTP3Transport returns a transport that sends every request over HTTP/3 on top of QUIC, many requests to the same
+// host are sent as streams over a single connection
+func newHTTP3Transport(config TransportConfig) *http3.Transport {
+	return &http3.Transport{
+		TLSClientConfig: config.TLSClientConfig,
+		QUICConfig: &quic.Config{
+			HandshakeIdleTimeout: config.DialTimeout,
+			MaxIdleTimeout:       config.IdleConnTimeout,
+			KeepAlivePeriod:      config.KeepAlive,
+		},
+	}
+}
+
+// parseAltSvc returns the first HTTP/3 alternative service advertised by the Alt-Svc header values, an alternative
+// without a host is on the same host as the origin. Cleared is true when the origin has withdrawn every alternative.
+func parseAltSvc(values []string, hostname string, now time.Time) (altService, bool, bool) {
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "clear" {
+				return altService{}, true, false
+			}
+			params := strings.Split(entry, ";")
+			protocol, authority, found := strings.Cut(strings.TrimSpace(params[0]), "=")
+			if !found || protocol != http3.NextProtoH3 {
+				continue
+			}
+			host, port, err := net.SplitHostPort(strings.Trim(authority, `"`))
+			if err != nil || port == "" {
+				continue
+			}
+			if host == "" {
+				host = hostname
+			}
+			service := altService{address: net.JoinHostPort(host, port), expires: now.Add(defaultAltSvcMaxAge)}
+			for _, param := range params[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); key == "ma" && err == nil {
+					service.expires = now.Add(time.Duration(seconds) * time.Second)
+				}
+			}
+			return service, false, true
+		}
+	}
+	return altService{}, false, false
+}
+
+// originAddr returns the host and port of the url, the port defaults to 443 as only https can be upgraded to HTTP/3
+func originAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// rewind returns a copy of the request with a fresh body, so that a request can be sent again after a transport has
+// already read the body. A request whose body cannot be read again is returned as it is.
+func rewind(req *http.Request) *http.Request {
+	if req.Body == nil || req.GetBody == nil {
+		return req
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return req
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone
+}
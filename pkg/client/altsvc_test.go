@@ -0,0 +1,196 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// protocolHandler responds with the protocol each request was made over
+var protocolHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(r.Proto))
+})
+
+// newQUICServer returns a HTTP/3 server listening on a local udp port using the certificate of the tls server, along
+// with the port it is listening on
+func newQUICServer(t *testing.T, mockServer *httptest.Server) (*http3.Server, int) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Expected no error listening on udp, but got %v", err)
+	}
+	server := &http3.Server{Handler: protocolHandler, TLSConfig: http3.ConfigureTLSConfig(mockServer.TLS.Clone())}
+	go func() {
+		_ = server.Serve(conn)
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = conn.Close()
+	})
+	return server, conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// newAltSvcServer returns a tls server that advertises HTTP/3 on the given port in the Alt-Svc header of each response
+func newAltSvcServer(port *atomic.Int32) *httptest.Server {
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Alt-Svc", fmt.Sprintf(`h3=":%d"; ma=60`, port.Load()))
+		protocolHandler(w, r)
+	}))
+	mockServer.EnableHTTP2 = true
+	mockServer.StartTLS()
+	return mockServer
+}
+
+func TestNewTransport_HTTP3(t *testing.T) {
+	t.Run("should send every request over HTTP/3", func(t *testing.T) {
+		mockServer := newProtocolServer(true, true, nil)
+		defer mockServer.Close()
+		_, port := newQUICServer(t, mockServer)
+
+		config := DefaultTransportConfig()
+		config.Protocol = ProtocolHTTP3
+		config.TLSClientConfig = trustServer(mockServer)
+		r := NewRequester(5*time.Second, "GET", nil, "")
+		r.UseTransport(NewTransport(config))
+
+		for range 3 {
+			response, err := r.MakeRequest(Request{URL: fmt.Sprintf("https://127.0.0.1:%d", port), Subdomain: "word"})
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if response.Protocol != "HTTP/3.0" || response.Body != "HTTP/3.0" {
+				t.Errorf("Expected protocol HTTP/3.0, but got %s and server saw %s", response.Protocol, response.Body)
+			}
+		}
+	})
+
+	t.Run("should fail when the server does not accept QUIC", func(t *testing.T) {
+		mockServer := newProtocolServer(true, true, nil)
+		defer mockServer.Close()
+
+		config := DefaultTransportConfig()
+		config.Protocol = ProtocolHTTP3
+		config.DialTimeout = 500 * time.Millisecond
+		config.TLSClientConfig = trustServer(mockServer)
+		r := NewRequester(2*time.Second, "GET", nil, "")
+		r.UseTransport(NewTransport(config))
+
+		if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"}); err == nil {
+			t.Errorf("Expected an error, but got none")
+		}
+	})
+}
+
+func TestNewTransport_AltSvc(t *testing.T) {
+	t.Run("should switch to HTTP/3 once the server advertises it", func(t *testing.T) {
+		var port atomic.Int32
+		mockServer := newAltSvcServer(&port)
+		defer mockServer.Close()
+		_, quicPort := newQUICServer(t, mockServer)
+		port.Store(int32(quicPort))
+
+		config := DefaultTransportConfig()
+		config.Protocol = ProtocolAltSvc
+		config.TLSClientConfig = trustServer(mockServer)
+		r := NewRequester(5*time.Second, "GET", nil, "")
+		r.UseTransport(NewTransport(config))
+
+		wantProtos := []string{"HTTP/2.0", "HTTP/3.0", "HTTP/3.0"}
+		for _, want := range wantProtos {
+			response, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"})
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if response.Protocol != want {
+				t.Errorf("Expected protocol %s, but got %s", want, response.Protocol)
+			}
+		}
+	})
+
+	t.Run("should fall back to tcp when HTTP/3 stops working", func(t *testing.T) {
+		var port atomic.Int32
+		mockServer := newAltSvcServer(&port)
+		defer mockServer.Close()
+		server, quicPort := newQUICServer(t, mockServer)
+		port.Store(int32(quicPort))
+
+		config := DefaultTransportConfig()
+		config.Protocol = ProtocolAltSvc
+		config.DialTimeout = 500 * time.Millisecond
+		config.TLSClientConfig = trustServer(mockServer)
+		r := NewRequester(5*time.Second, "GET", nil, "")
+		r.UseTransport(NewTransport(config))
+
+		for range 2 {
+			if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"}); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+		}
+		_ = server.Close()
+
+		response, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"})
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if response.Protocol != "HTTP/2.0" {
+			t.Errorf("Expected protocol HTTP/2.0, but got %s", response.Protocol)
+		}
+	})
+}
+
+func TestParseAltSvc(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		values      []string
+		wantService altService
+		wantCleared bool
+		wantOK      bool
+	}{
+		{
+			name:        "should parse an alternative on the same host",
+			values:      []string{`h3=":443"; ma=3600`},
+			wantService: altService{address: "example.com:443", expires: now.Add(time.Hour)},
+			wantOK:      true,
+		},
+		{
+			name:        "should parse an alternative on another host with the default max age",
+			values:      []string{`h3="alt.example.com:8443"`},
+			wantService: altService{address: "alt.example.com:8443", expires: now.Add(defaultAltSvcMaxAge)},
+			wantOK:      true,
+		},
+		{
+			name:        "should skip alternatives that are not HTTP/3",
+			values:      []string{`h2=":443", h3-29=":443"`, `h3=":8443"; persist=1`},
+			wantService: altService{address: "example.com:8443", expires: now.Add(defaultAltSvcMaxAge)},
+			wantOK:      true,
+		},
+		{
+			name:        "should clear every alternative",
+			values:      []string{"clear"},
+			wantCleared: true,
+		},
+		{
+			name:   "should ignore an alternative without a port",
+			values: []string{`h3="example.com"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, cleared, ok := parseAltSvc(tt.values, "example.com", now)
+			if ok != tt.wantOK || cleared != tt.wantCleared {
+				t.Fatalf("Expected ok %v and cleared %v, but got %v and %v", tt.wantOK, tt.wantCleared, ok, cleared)
+			}
+			if service != tt.wantService {
+				t.Errorf("Expected %+v, but got %+v", tt.wantService, service)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"github.com/ch55secake/dizzy/pkg/output"
 	"io"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -15,91 +18,132 @@ import (
 
 // Requester is the default implementation for the http client
 type Requester struct {
-	Timeout           time.Duration     `json:"timeout"`
-	Method            string            `json:"method"`
-	Headers           map[string]string `json:"header"`
-	OnlyOutputFailure bool              `json:"only_output_failure"`
+	Timeout time.Duration     `json:"timeout"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"header"`
+	Body    string            `json:"body"`
+	Retry   RetryPolicy       `json:"retry"`
+	client  *http.Client
 }
 
-// NewRequester will create a new requester object that will allow you to set a timeout
-func NewRequester(timeout time.Duration, method string, headers map[string]string, onlyFailure bool) *Requester {
-	if timeout == 0 || method == "" {
-		output.PrintCyanMessage("Cannot have a timeout of zero, will default to a timeout of ten seconds", true)
-		return &Requester{
-			Timeout:           10 * time.Second,
-			Method:            "GET",
-			Headers:           headers,
-			OnlyOutputFailure: onlyFailure,
-		}
+// NewRequester will create a new requester object that will allow you to set a timeout, the header values and body can
+// contain the keyword which will be replaced with the word for each request. Every request made by the requester is
+// sent through the same client using a transport with the default config, which can be replaced with UseTransport.
+func NewRequester(timeout time.Duration, method string, headers map[string]string, body string) *Requester {
+	r := &Requester{
+		Timeout: timeout,
+		Method:  method,
+		Headers: headers,
+		Body:    body,
+	}
+	if timeout == 0 {
+		log.Warnf("Cannot have a timeout of zero, will default to a timeout of ten seconds")
+		r.Timeout = 10 * time.Second
 	}
-	return &Requester{
-		Timeout:           timeout,
-		Method:            method,
-		Headers:           headers,
-		OnlyOutputFailure: onlyFailure,
+	if method == "" {
+		r.Method = http.MethodGet
 	}
+	r.UseTransport(defaultTransport)
+	return r
 }
 
-// MakeRequest will return either the error if it occurs or the length of the response body,
-// which could indicate that there is something on the path that was just requested for.
+// MakeRequest will return the response for the given request, which could indicate that there is something on the path
+// that was just requested for. Requests that fail with a transient error or status code are sent again following the
+// retry policy, the response records how many attempts were made. If an error occurs it is returned alongside the
+// response so the caller can decide what to do with it, printing is left to whoever consumes the response.
 func (r *Requester) MakeRequest(request Request) (Response, error) {
-	c := http.Client{
-		Timeout: r.Timeout,
+	for attempt := 1; ; attempt++ {
+		response, err := r.makeRequest(request)
+		response.Attempts = attempt
+		if attempt >= r.Retry.MaxAttempts || !r.Retry.retryable(&response, err) {
+			return response, err
+		}
+		wait := r.Retry.wait(attempt)
+		log.Debugf("Attempt %d of %s failed, retrying in %v", attempt, response.URL, wait)
+		time.Sleep(wait)
+		if r.Retry.Throttle != nil {
+			r.Retry.Throttle()
+		}
 	}
+}
 
-	c.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
-		return http.ErrUseLastResponse
+// makeRequest will send the request once, a request that could not be sent has no status code unless it timed out
+func (r *Requester) makeRequest(request Request) (Response, error) {
+	started := time.Now()
+	resp, body, address, err := r.sendRequest(request, r.httpClient())
+	response := Response{
+		BodyLength: len(body),
+		Words:      countWords(body),
+		Lines:      countLines(body),
+		Body:       string(body),
+		Subdomain:  request.Subdomain,
+		Payload:    request.Payload,
+		URL:        request.ToString(),
+		Method:     r.Method,
+		Duration:   time.Since(started),
+		Address:    address,
+		Title:      extractTitle(body),
+	}
+	if u, parseErr := url.Parse(response.URL); parseErr == nil {
+		response.Host = u.Host
+	}
+	if resp != nil {
+		response.StatusCode = resp.StatusCode
+		response.Protocol = resp.Proto
+		response.Location = resp.Header.Get("Location")
+		response.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
 	}
-
-	bodyLength, statusCode, err := r.sendRequest(request, c)
 	if err != nil {
+		response.Error = err.Error()
+		response.TLSError = describeTLSError(err)
 		if errors.Is(err, context.DeadlineExceeded) {
-			log.Errorf("Request timed out")
-			return Response{
-				StatusCode: 408,
-				BodyLength: 0,
-			}, err
+			log.Debugf("Request timed out")
+			response.StatusCode = 408
+			response.BodyLength = 0
 		}
 	}
 
-	response := Response{
-		StatusCode: statusCode,
-		BodyLength: bodyLength,
-		Subdomain:  request.Subdomain,
-	}
-	if r.OnlyOutputFailure && response.StatusCode/100 != 2 {
-		output.PrintCyanMessage(fmt.Sprintf("%-3s %-20s %-10d %-15d", "", response.Subdomain, response.StatusCode, response.BodyLength), true)
-	} else if !r.OnlyOutputFailure {
-		output.PrintCyanMessage(fmt.Sprintf("%-3s %-20s %-10d %-15d", "", response.Subdomain, response.StatusCode, response.BodyLength), true)
-	}
-	return response, nil
+	return response, err
 }
 
-// sendRequest will send the request with the provided method from the request model.
-func (r *Requester) sendRequest(request Request, client http.Client) (int, int, error) {
+// sendRequest will send the request with the provided method from the request model, the body of the response is read
+// and closed before returning. The address that the host resolved to and was connected to is also returned.
+func (r *Requester) sendRequest(request Request, client *http.Client) (*http.Response, []byte, string, error) {
 	valid, invalidError := isValidHTTPMethod(r)
 	if valid {
-		req, err := http.NewRequest(r.Method, request.ToString(), nil)
+		var requestBody io.Reader
+		if r.Body != "" {
+			requestBody = strings.NewReader(request.Substitute(r.Body))
+		}
+
+		req, err := http.NewRequest(r.Method, request.ToString(), requestBody)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"method":  r.Method,
 				"request": request.ToString(),
 			}).Errorf("Error creating request.")
-			return 0, 400, fmt.Errorf("error occurred creating request: %w", err)
+			return nil, nil, "", fmt.Errorf("error occurred creating request: %w", err)
 		}
 
 		if r.Headers != nil {
 			for key, value := range r.Headers {
-				req.Header.Set(key, value)
+				req.Header.Set(key, request.Substitute(value))
 			}
 		}
 
+		var address string
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				address = info.Conn.RemoteAddr().String()
+			},
+		}))
+
 		resp, err := client.Do(req)
 		if err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
-				return 0, 400, context.DeadlineExceeded
+				return nil, nil, address, context.DeadlineExceeded
 			}
-			return 0, 400, fmt.Errorf("error occurred sending request: %w", err)
+			return nil, nil, address, fmt.Errorf("error occurred sending request: %w", err)
 		}
 		defer func(Body io.ReadCloser) {
 			if closeErr := Body.Close(); closeErr != nil {
@@ -109,12 +153,25 @@ func (r *Requester) sendRequest(request Request, client http.Client) (int, int,
 
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return 0, 400, fmt.Errorf("error occurred reading response body: %w", err)
+			return resp, nil, address, fmt.Errorf("error occurred reading response body: %w", err)
 		}
 
-		return len(body), resp.StatusCode, nil
+		return resp, body, address, nil
+	}
+	return nil, nil, "", invalidError
+}
+
+// countWords will return the number of whitespace separated words in the body
+func countWords(body []byte) int {
+	return len(bytes.Fields(body))
+}
+
+// countLines will return the number of lines in the body, a body without a trailing newline still counts as a line
+func countLines(body []byte) int {
+	if len(body) == 0 {
+		return 0
 	}
-	return 0, 400, invalidError
+	return bytes.Count(body, []byte("\n")) + 1
 }
 
 // isValidHTTPMethod will determine whether attempted http method is actually a valid operation
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -146,6 +147,11 @@ func TestMakeRequest_WithHeaders(t *testing.T) {
 }
 
 func TestMakeRequest_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+
 	tests := []struct {
 		name      string
 		request   Request
@@ -183,7 +189,7 @@ func TestMakeRequest_Error(t *testing.T) {
 			},
 			request: Request{
 				// Valid GET request
-				URL: "http://example.com",
+				URL: mockServer.URL,
 			},
 			wantError: false,
 		},
@@ -206,3 +212,112 @@ func TestMakeRequest_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeRequest_CountsWordsAndLines(t *testing.T) {
+	t.Run("should count the words and lines in the response body", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte("not found\nplease try again"))
+			if err != nil {
+				return
+			}
+		}))
+		defer mockServer.Close()
+
+		r := Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		response, err := r.MakeRequest(Request{URL: mockServer.URL})
+		if err != nil {
+			t.Errorf("Expected no error, but got %v", err)
+		}
+
+		if response.Words != 5 {
+			t.Errorf("Expected 5 words, but got %d", response.Words)
+		}
+		if response.Lines != 2 {
+			t.Errorf("Expected 2 lines, but got %d", response.Lines)
+		}
+	})
+}
+
+func TestMakeRequest_SubstitutesKeyword(t *testing.T) {
+	t.Run("should replace the keyword in header values and the body", func(t *testing.T) {
+		var header, body string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("X-User")
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		r := NewRequester(1*time.Second, "POST", map[string]string{"X-User": "FUZZ"}, `{"user":"FUZZ"}`)
+
+		_, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "admin", Mode: ModeKeyword})
+		if err != nil {
+			t.Errorf("Expected no error, but got %v", err)
+		}
+
+		if header != "admin" {
+			t.Errorf("Expected header to be admin, but got %q", header)
+		}
+		if body != `{"user":"admin"}` {
+			t.Errorf("Expected body to contain admin, but got %q", body)
+		}
+	})
+}
+
+func TestNewRequester(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		method      string
+		wantTimeout time.Duration
+		wantMethod  string
+	}{
+		{
+			name:        "should keep the method when defaulting the timeout",
+			timeout:     0,
+			method:      "POST",
+			wantTimeout: 10 * time.Second,
+			wantMethod:  "POST",
+		},
+		{
+			name:        "should keep the timeout when defaulting the method",
+			timeout:     5 * time.Second,
+			method:      "",
+			wantTimeout: 5 * time.Second,
+			wantMethod:  "GET",
+		},
+		{
+			name:        "should default both the timeout and the method",
+			wantTimeout: 10 * time.Second,
+			wantMethod:  "GET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer mockServer.Close()
+
+			r := NewRequester(tt.timeout, tt.method, nil, "")
+			if r.Timeout != tt.wantTimeout {
+				t.Errorf("Expected timeout %v, but got %v", tt.wantTimeout, r.Timeout)
+			}
+			if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "admin"}); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if method != tt.wantMethod {
+				t.Errorf("Expected a %s request, but got %s", tt.wantMethod, method)
+			}
+		})
+	}
+}
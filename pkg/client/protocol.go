@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/net/http2"
+)
+
+// Protocol is the version of http that requests are sent with
+type Protocol string
+
+const (
+	// ProtocolAuto uses HTTP/2 when the server offers it during the TLS handshake and HTTP/1.1 otherwise
+	ProtocolAuto Protocol = "auto"
+	// ProtocolHTTP1 only ever uses HTTP/1.1, even when the server offers HTTP/2
+	ProtocolHTTP1 Protocol = "http1.1"
+	// ProtocolHTTP2 only ever uses HTTP/2 over TLS, a server that does not offer HTTP/2 fails the request
+	ProtocolHTTP2 Protocol = "h2"
+	// ProtocolH2C uses HTTP/2 over cleartext with prior knowledge, without upgrading from HTTP/1.1 first
+	ProtocolH2C Protocol = "h2c"
+	// ProtocolHTTP3 only ever uses HTTP/3 over QUIC, a server that does not accept QUIC fails the request
+	ProtocolHTTP3 Protocol = "h3"
+	// ProtocolAltSvc starts like auto and switches to HTTP/3 for any host that advertises it in an Alt-Svc header
+	ProtocolAltSvc Protocol = "alt-svc"
+)
+
+// Protocols are the protocols that can be chosen from
+var Protocols = []Protocol{ProtocolAuto, ProtocolHTTP1, ProtocolHTTP2, ProtocolH2C, ProtocolHTTP3, ProtocolAltSvc}
+
+// ParseProtocol returns the protocol with the given name, an empty name is auto
+func ParseProtocol(name string) (Protocol, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return ProtocolAuto, nil
+	case "http1.1", "http1", "h1":
+		return ProtocolHTTP1, nil
+	case "h2", "http2":
+		return ProtocolHTTP2, nil
+	case "h2c":
+		return ProtocolH2C, nil
+	case "h3", "http3":
+		return ProtocolHTTP3, nil
+	case "alt-svc", "altsvc":
+		return ProtocolAltSvc, nil
+	}
+	return "", fmt.Errorf("error unknown protocol %q, must be one of %v", name, Protocols)
+}
+
+// newHTTP2Transport returns a transport that sends every request over HTTP/2, many requests to the same host are sent as
+// streams over a few connections rather than each needing its own connection. With cleartext the connection is plain
+// tcp and the server is expected to speak HTTP/2 straight away.
+func newHTTP2Transport(config TransportConfig, dialer *net.Dialer, cleartext bool) *http2.Transport {
+	transport := &http2.Transport{
+		TLSClientConfig: config.TLSClientConfig,
+		IdleConnTimeout: config.IdleConnTimeout,
+		ReadIdleTimeout: config.KeepAlive,
+		DialTLSContext: func(ctx context.Context, network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+			return dialHTTP2(ctx, dialer, network, addr, tlsConfig)
+		},
+	}
+	if cleartext {
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	return transport
+}
+
+// dialHTTP2 will open a TLS connection that the server has agreed to speak HTTP/2 over, a server that only offers
+// HTTP/1.1 during the handshake is an error rather than being sent HTTP/2 frames it cannot understand. The http2
+// transport always passes a tls config with the server name already set.
+func dialHTTP2(ctx context.Context, dialer *net.Dialer, network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	raw, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, tlsConfig)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		_ = raw.Close()
+		return nil, err
+	}
+	if protocol := conn.ConnectionState().NegotiatedProtocol; protocol != http2.NextProtoTLS {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error server at %s does not support HTTP/2, negotiated %q", addr, protocol)
+	}
+	return conn, nil
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// newProtocolServer returns a server that responds with the protocol each request was made over, tls servers offer
+// HTTP/2 during the handshake when http2 is true and cleartext servers accept HTTP/2 with prior knowledge. Connections
+// opened to the server are counted when given a counter.
+func newProtocolServer(useTLS bool, http2Enabled bool, connections *atomic.Int32) *httptest.Server {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Proto))
+	})
+	if !useTLS && http2Enabled {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+	mockServer := httptest.NewUnstartedServer(handler)
+	mockServer.EnableHTTP2 = http2Enabled
+	if connections != nil {
+		mockServer.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				connections.Add(1)
+			}
+		}
+	}
+	if useTLS {
+		mockServer.StartTLS()
+	} else {
+		mockServer.Start()
+	}
+	return mockServer
+}
+
+// trustServer returns a tls config that trusts the certificate of the server
+func trustServer(mockServer *httptest.Server) *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(mockServer.Certificate())
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+}
+
+func TestNewTransport_Protocol(t *testing.T) {
+	tests := []struct {
+		name         string
+		protocol     Protocol
+		useTLS       bool
+		http2Enabled bool
+		wantProto    string
+		wantError    bool
+	}{
+		{
+			name:         "should use HTTP/2 when the server offers it",
+			protocol:     ProtocolAuto,
+			useTLS:       true,
+			http2Enabled: true,
+			wantProto:    "HTTP/2.0",
+		},
+		{
+			name:         "should fall back to HTTP/1.1 when the server does not offer HTTP/2",
+			protocol:     ProtocolAuto,
+			useTLS:       true,
+			http2Enabled: false,
+			wantProto:    "HTTP/1.1",
+		},
+		{
+			name:         "should only use HTTP/1.1 even when the server offers HTTP/2",
+			protocol:     ProtocolHTTP1,
+			useTLS:       true,
+			http2Enabled: true,
+			wantProto:    "HTTP/1.1",
+		},
+		{
+			name:         "should force HTTP/2 over TLS",
+			protocol:     ProtocolHTTP2,
+			useTLS:       true,
+			http2Enabled: true,
+			wantProto:    "HTTP/2.0",
+		},
+		{
+			name:         "should fail when forcing HTTP/2 and the server does not offer it",
+			protocol:     ProtocolHTTP2,
+			useTLS:       true,
+			http2Enabled: false,
+			wantError:    true,
+		},
+		{
+			name:         "should use HTTP/2 over cleartext with prior knowledge",
+			protocol:     ProtocolH2C,
+			useTLS:       false,
+			http2Enabled: true,
+			wantProto:    "HTTP/2.0",
+		},
+		{
+			name:         "should use HTTP/1.1 over cleartext by default",
+			protocol:     ProtocolAuto,
+			useTLS:       false,
+			http2Enabled: true,
+			wantProto:    "HTTP/1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := newProtocolServer(tt.useTLS, tt.http2Enabled, nil)
+			defer mockServer.Close()
+
+			config := DefaultTransportConfig()
+			config.Protocol = tt.protocol
+			if tt.useTLS {
+				config.TLSClientConfig = trustServer(mockServer)
+			}
+			r := NewRequester(5*time.Second, "GET", nil, "")
+			r.UseTransport(NewTransport(config))
+
+			response, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Expected error to be %v, but got %v", tt.wantError, err)
+			}
+			if tt.wantError {
+				return
+			}
+			if response.Protocol != tt.wantProto {
+				t.Errorf("Expected protocol %s, but got %s", tt.wantProto, response.Protocol)
+			}
+			if response.Body != tt.wantProto {
+				t.Errorf("Expected server to see protocol %s, but got %s", tt.wantProto, response.Body)
+			}
+		})
+	}
+}
+
+func TestNewTransport_HTTP2Multiplexes(t *testing.T) {
+	t.Run("should send every request as a stream over a single connection", func(t *testing.T) {
+		var connections atomic.Int32
+		mockServer := newProtocolServer(false, true, &connections)
+		defer mockServer.Close()
+
+		config := DefaultTransportConfig()
+		config.Protocol = ProtocolH2C
+		r := NewRequester(5*time.Second, "GET", nil, "")
+		r.UseTransport(NewTransport(config))
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 20 {
+					if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"}); err != nil {
+						t.Errorf("Expected no error, but got %v", err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got := connections.Load(); got != 1 {
+			t.Errorf("Expected 1 connection, but %d were opened", got)
+		}
+	})
+}
+
+func TestParseProtocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      Protocol
+		wantError bool
+	}{
+		{name: "should default to auto", input: "", want: ProtocolAuto},
+		{name: "should parse http1.1", input: "http1.1", want: ProtocolHTTP1},
+		{name: "should parse h2 ignoring case", input: "H2", want: ProtocolHTTP2},
+		{name: "should parse h2c", input: "h2c", want: ProtocolH2C},
+		{name: "should reject an unknown protocol", input: "spdy", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProtocol(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Expected error to be %v, but got %v", tt.wantError, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
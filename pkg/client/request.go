@@ -1,11 +1,58 @@
 package client
 
-import log "github.com/sirupsen/logrus"
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 
-// Request structure will be used to send requests and later on as flags as part the command
+	log "github.com/sirupsen/logrus"
+)
+
+// Keyword is replaced with the word from the wordlist wherever it is placed in the url, headers or body of a request
+const Keyword = "FUZZ"
+
+// HasKeyword returns whether the keyword has been placed in the given url, header or body
+func HasKeyword(s string) bool {
+	return strings.Contains(s, Keyword)
+}
+
+// Mode is where the word from the wordlist is placed when building the url for a request
+type Mode string
+
+const (
+	// ModeDirectory appends the word to the url as a path, this is the default
+	ModeDirectory Mode = "dir"
+	// ModeSubdomain prepends the word to the host of the url as a subdomain
+	ModeSubdomain Mode = "subdomain"
+	// ModeDNS resolves each word as a subdomain without making a http request, unless the resolved subdomains are being
+	// probed, in which case the requests are made as they would be in subdomain mode
+	ModeDNS Mode = "dns"
+	// ModeKeyword only replaces the keyword with the word, it is used automatically when the keyword has been placed
+	// anywhere in the request rather than being chosen by the user
+	ModeKeyword Mode = "keyword"
+)
+
+// ParseMode will return the mode matching the given name, an empty name will default to directory mode
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(name)); mode {
+	case ModeDirectory, ModeSubdomain, ModeDNS:
+		return mode, nil
+	case "":
+		return ModeDirectory, nil
+	default:
+		return "", fmt.Errorf("unsupported mode: %s", name)
+	}
+}
+
+// Request structure will be used to send requests and later on as flags as part the command. When more than one
+// wordlist is used the payload holds the word for each keyword, the subdomain is then the word from the first wordlist.
 type Request struct {
-	URL       string `json:"url"`
-	Subdomain string `json:"subdomain"`
+	URL       string            `json:"url"`
+	Subdomain string            `json:"subdomain"`
+	Mode      Mode              `json:"mode"`
+	Payload   map[string]string `json:"payload,omitempty"`
 }
 
 // EmptyRequest used for when wordlist has no data should be attached to an error
@@ -20,11 +67,65 @@ func (req Request) isValid() bool {
 	return req.Subdomain != ""
 }
 
-// ToString will combine the given subdomain with the url unless the subdomain is nil
+// ToString will combine the given subdomain with the url unless the subdomain is nil. If the url contains the keyword,
+// or the keyword has been placed elsewhere in the request, it is replaced with the word, otherwise in subdomain mode
+// the word is prepended to the host keeping the scheme, port and path of the url and in directory mode the word is
+// appended to the url as a path.
 func (req Request) ToString() string {
-	if req.isValid() {
-		log.Debugf("Concatenated url request will be made with: %v", req.URL+"/"+req.Subdomain)
-		return req.URL + "/" + req.Subdomain
+	if !req.isValid() {
+		return req.URL
+	}
+	if req.Mode == ModeKeyword || req.hasKeyword(req.URL) {
+		return req.Substitute(req.URL)
+	}
+	if req.Mode == ModeSubdomain || req.Mode == ModeDNS {
+		return req.subdomainURL()
+	}
+	log.Debugf("Concatenated url request will be made with: %v", req.URL+"/"+req.Subdomain)
+	return req.URL + "/" + req.Subdomain
+}
+
+// subdomainURL will prepend the subdomain to the host of the url, if the url cannot be parsed it is returned as is
+func (req Request) subdomainURL() string {
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Host == "" {
+		log.Debugf("Unable to add subdomain %v to url: %v", req.Subdomain, req.URL)
+		return req.URL
+	}
+	u.Host = req.Subdomain + "." + u.Host
+	log.Debugf("Subdomain request will be made with: %v", u.String())
+	return u.String()
+}
+
+// hasKeyword returns whether any of the keywords in the payload, or the default keyword without one, is in the string
+func (req Request) hasKeyword(s string) bool {
+	if req.Payload == nil {
+		return HasKeyword(s)
+	}
+	for keyword := range req.Payload {
+		if strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Substitute will replace every occurrence of the keyword in the given string with the word, or every keyword in the
+// payload with its word. Longer keywords are replaced first so that a keyword containing another is not broken up.
+func (req Request) Substitute(s string) string {
+	if req.Payload == nil {
+		return strings.ReplaceAll(s, Keyword, req.Subdomain)
+	}
+	keywords := make([]string, 0, len(req.Payload))
+	for keyword := range req.Payload {
+		keywords = append(keywords, keyword)
+	}
+	slices.SortFunc(keywords, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+	pairs := make([]string, 0, len(keywords)*2)
+	for _, keyword := range keywords {
+		pairs = append(pairs, keyword, req.Payload[keyword])
 	}
-	return req.URL
+	return strings.NewReplacer(pairs...).Replace(s)
 }
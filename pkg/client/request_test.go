@@ -14,3 +14,92 @@ func TestRequest_ToString(t *testing.T) {
 		}
 	})
 }
+
+func TestRequest_ToString_Subdomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		request Request
+		want    string
+	}{
+		{
+			name:    "should prepend the subdomain to the host",
+			request: Request{URL: "https://example.com", Subdomain: "admin", Mode: ModeSubdomain},
+			want:    "https://admin.example.com",
+		},
+		{
+			name:    "should keep the scheme, port and path",
+			request: Request{URL: "http://example.com:8080/api/v1?debug=true", Subdomain: "dev", Mode: ModeSubdomain},
+			want:    "http://dev.example.com:8080/api/v1?debug=true",
+		},
+		{
+			name:    "should return the url when there is no subdomain",
+			request: Request{URL: "https://example.com", Mode: ModeSubdomain},
+			want:    "https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.request.ToString(); got != tt.want {
+				t.Errorf("ToString() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_ToString_Keyword(t *testing.T) {
+	tests := []struct {
+		name    string
+		request Request
+		want    string
+	}{
+		{
+			name:    "should replace the keyword in the path",
+			request: Request{URL: "http://example.com/api/users/FUZZ/profile", Subdomain: "admin"},
+			want:    "http://example.com/api/users/admin/profile",
+		},
+		{
+			name:    "should replace the keyword in the query string",
+			request: Request{URL: "http://example.com/search?q=FUZZ&page=1", Subdomain: "admin"},
+			want:    "http://example.com/search?q=admin&page=1",
+		},
+		{
+			name:    "should replace the keyword in the host",
+			request: Request{URL: "http://FUZZ.example.com/", Subdomain: "dev"},
+			want:    "http://dev.example.com/",
+		},
+		{
+			name:    "should not append the word when the keyword is elsewhere in the request",
+			request: Request{URL: "http://example.com/login", Subdomain: "admin", Mode: ModeKeyword},
+			want:    "http://example.com/login",
+		},
+		{
+			name:    "should append the word when there is no keyword",
+			request: Request{URL: "http://example.com", Subdomain: "admin"},
+			want:    "http://example.com/admin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.request.ToString(); got != tt.want {
+				t.Errorf("ToString() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_Substitute_Payload(t *testing.T) {
+	t.Run("should replace every keyword in the payload", func(t *testing.T) {
+		request := Request{
+			URL:       "http://example.com/login?user=USER&username=USERNAME&pass=PASS",
+			Subdomain: "admin",
+			Payload:   map[string]string{"USER": "admin", "USERNAME": "root", "PASS": "hunter2"},
+		}
+
+		want := "http://example.com/login?user=admin&username=root&pass=hunter2"
+		if got := request.ToString(); got != want {
+			t.Errorf("ToString() = %q; want %q", got, want)
+		}
+	})
+}
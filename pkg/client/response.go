@@ -1,9 +1,66 @@
 package client
 
-// Response that the client will map too, this tool only cares about statusCode and bodyLength so that is all that is
-// mapped
+import (
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/dns"
+)
+
+// titleRegex will find the title of a html page
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Response that the client will map too, this tool mostly cares about statusCode and the size of the body, alongside
+// what was requested and how long it took so results can be filtered and written out by the caller. The body itself is
+// only kept around for matching and is never serialised.
 type Response struct {
-	StatusCode int    `json:"status_code"`
-	BodyLength int    `json:"body_length"`
-	Subdomain  string `json:"subdomain"`
+	StatusCode int               `json:"status_code"`
+	BodyLength int               `json:"body_length"`
+	Words      int               `json:"words"`
+	Lines      int               `json:"lines"`
+	Body       string            `json:"-"`
+	Subdomain  string            `json:"subdomain"`
+	Payload    map[string]string `json:"payload,omitempty"`
+	URL        string            `json:"url"`
+	Path       string            `json:"path,omitempty"`
+	Method     string            `json:"method"`
+	Protocol   string            `json:"protocol,omitempty"`
+	Duration   time.Duration     `json:"duration"`
+	Attempts   int               `json:"attempts"`
+	Location   string            `json:"location,omitempty"`
+	Host       string            `json:"host,omitempty"`
+	Address    string            `json:"address,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	DNS        *dns.Records      `json:"dns,omitempty"`
+	RetryAfter time.Duration     `json:"-"`
+	Error      string            `json:"error,omitempty"`
+	TLSError   string            `json:"tls_error,omitempty"`
+}
+
+// parseRetryAfter returns how long the Retry-After header asks to wait, given either as a number of seconds or as a http
+// date, a missing or invalid header returns zero
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// extractTitle returns the title of the html page in the body, or an empty string if it does not have one
+func extractTitle(body []byte) string {
+	match := titleRegex.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
 }
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_extractTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "should return the title of the page", body: "<html><head><title>Admin Panel</title></head></html>", want: "Admin Panel"},
+		{name: "should ignore case and attributes", body: `<TITLE lang="en">Login</TITLE>`, want: "Login"},
+		{name: "should collapse whitespace and unescape entities", body: "<title>\n  Tom &amp; Jerry\n</title>", want: "Tom & Jerry"},
+		{name: "should return nothing when there is no title", body: `{"message": "success"}`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractTitle([]byte(tt.body)); got != tt.want {
+				t.Errorf("extractTitle() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "should parse a number of seconds", value: "120", want: 2 * time.Minute},
+		{name: "should parse a http date", value: "Mon, 01 Jan 2024 12:00:30 GMT", want: 30 * time.Second},
+		{name: "should not wait for a date in the past", value: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0},
+		{name: "should ignore an invalid header", value: "soon", want: 0},
+		{name: "should ignore a missing header", value: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
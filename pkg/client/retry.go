@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// maxRetryBackoff is the longest that is waited between two attempts of the same request
+const maxRetryBackoff = 10 * time.Second
+
+// DefaultRetryStatuses are the status codes that are retried by default, 429 and 503 are left to the dispatcher which
+// slows the whole scan down rather than retrying a single request
+var DefaultRetryStatuses = []int{502, 504}
+
+// RetryPolicy decides whether a request that failed with a transient error or status code is sent again, and how long
+// to wait before each attempt. The wait starts at the backoff and doubles after each attempt. Throttle is called after
+// the backoff and before every retry, so that retries are held back by the same rate limit, delay and pauses as every
+// other request rather than being sent straight to a target that is already struggling.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	Statuses    []int
+	Throttle    func()
+}
+
+// retryable returns whether the request should be sent again after the response or error it returned
+func (p RetryPolicy) retryable(response *Response, err error) bool {
+	if err != nil {
+		return isRetryableError(err)
+	}
+	return slices.Contains(p.Statuses, response.StatusCode)
+}
+
+// wait returns how long to wait after the given attempt before sending the request again
+func (p RetryPolicy) wait(attempt int) time.Duration {
+	wait := p.Backoff
+	for range attempt - 1 {
+		wait *= 2
+		if wait >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return wait
+}
+
+// isRetryableError returns whether the error is a transient failure of the connection that may succeed if the request
+// is sent again, such as a timeout, reset connection or temporary dns failure. Errors building the request, such as an
+// invalid url or method, or failing to verify a certificate are never retried.
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMakeRequest_Retry(t *testing.T) {
+	t.Run("should retry a retryable status code and record the attempts", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		r := Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+			Retry:   RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Statuses: DefaultRetryStatuses},
+		}
+
+		response, err := r.MakeRequest(Request{URL: mockServer.URL})
+		if err != nil {
+			t.Errorf("Expected no error, but got %v", err)
+		}
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, but got %d", response.StatusCode)
+		}
+		if response.Attempts != 2 {
+			t.Errorf("Expected 2 attempts, but got %d", response.Attempts)
+		}
+	})
+
+	t.Run("should wait for the throttle before every retry", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer mockServer.Close()
+
+		var throttled atomic.Int32
+		r := Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+			Retry: RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+				Statuses:    DefaultRetryStatuses,
+				Throttle:    func() { throttled.Add(1) },
+			},
+		}
+
+		response, _ := r.MakeRequest(Request{URL: mockServer.URL})
+		if response.Attempts != 3 {
+			t.Errorf("Expected 3 attempts, but got %d", response.Attempts)
+		}
+		// the first attempt has already been throttled by the worker, only the retries need to be
+		if throttled.Load() != 2 {
+			t.Errorf("Expected the throttle to be waited for twice, but got %d", throttled.Load())
+		}
+	})
+
+	t.Run("should give up after the maximum attempts without a fabricated status code", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		url := mockServer.URL
+		mockServer.Close()
+
+		r := Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+			Retry:   RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		}
+
+		response, err := r.MakeRequest(Request{URL: url})
+		if err == nil {
+			t.Errorf("Expected error, but got nil")
+		}
+		if response.StatusCode != 0 {
+			t.Errorf("Expected no status code, but got %d", response.StatusCode)
+		}
+		if response.Attempts != 3 {
+			t.Errorf("Expected 3 attempts, but got %d", response.Attempts)
+		}
+	})
+
+	t.Run("should not retry an invalid request", func(t *testing.T) {
+		r := Requester{
+			Timeout: 1 * time.Second,
+			Method:  "INVALID",
+			Retry:   RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		}
+
+		response, _ := r.MakeRequest(Request{URL: "http://localhost"})
+		if response.Attempts != 1 {
+			t.Errorf("Expected 1 attempt, but got %d", response.Attempts)
+		}
+	})
+}
+
+func TestRetryPolicy_wait(t *testing.T) {
+	policy := RetryPolicy{Backoff: 500 * time.Millisecond}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 500 * time.Millisecond},
+		{attempt: 2, want: 1 * time.Second},
+		{attempt: 3, want: 2 * time.Second},
+		{attempt: 10, want: maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("should wait %v after attempt %d", tt.want, tt.attempt), func(t *testing.T) {
+			if got := policy.wait(tt.attempt); got != tt.want {
+				t.Errorf("wait() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "should retry a timeout", err: context.DeadlineExceeded, want: true},
+		{name: "should retry a reset connection", err: fmt.Errorf("error: %w", syscall.ECONNRESET), want: true},
+		{name: "should retry an unexpected eof", err: fmt.Errorf("error: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "should retry a temporary dns failure", err: &net.DNSError{IsTemporary: true}, want: true},
+		{name: "should not retry a host that does not exist", err: &net.DNSError{IsNotFound: true}, want: false},
+		{name: "should not retry any other error", err: errors.New("invalid HTTP method"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tlsVersions are the names of the TLS versions that can be given as the minimum version
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSOptions decide how connections made over TLS are set up, the zero value verifies certificates against the system
+// roots just like the default config
+type TLSOptions struct {
+	// Insecure skips verifying the certificate of the server, for internal hosts with self signed certificates
+	Insecure bool
+	// CABundle is the path to a pem file of certificates that are trusted alongside the system roots
+	CABundle string
+	// ClientCert and ClientKey are the paths to the pem encoded certificate and key sent to servers that ask for one,
+	// the key can be left empty if it is in the same file as the certificate
+	ClientCert string
+	ClientKey  string
+	// MinVersion is the lowest TLS version that will be used, one of 1.0, 1.1, 1.2 or 1.3
+	MinVersion string
+	// ServerName is sent as the SNI and used to verify the certificate instead of the host being requested
+	ServerName string
+}
+
+// NewTLSConfig returns the tls config for the options, an error is returned if any of the files cannot be loaded
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: options.Insecure, // #nosec G402
+		ServerName:         options.ServerName,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if options.MinVersion != "" {
+		version, ok := tlsVersions[strings.TrimSpace(options.MinVersion)]
+		if !ok {
+			return nil, fmt.Errorf("error unknown TLS version %q, must be one of 1.0, 1.1, 1.2 or 1.3",
+				options.MinVersion)
+		}
+		config.MinVersion = version // #nosec G402
+	}
+
+	if options.CABundle != "" {
+		pem, err := os.ReadFile(options.CABundle) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("error no certificates found in CA bundle %s", options.CABundle)
+		}
+		config.RootCAs = pool
+	}
+
+	if options.ClientCert == "" && options.ClientKey != "" {
+		return nil, errors.New("error a client key was given without a client certificate")
+	}
+	if options.ClientCert != "" {
+		key := options.ClientKey
+		if key == "" {
+			key = options.ClientCert
+		}
+		certificate, err := tls.LoadX509KeyPair(options.ClientCert, key)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// describeTLSError returns why the TLS handshake failed if the error came from one, so that certificate problems can
+// be told apart from the target being unreachable. Any other error returns an empty string.
+func describeTLSError(err error) string {
+	if err == nil {
+		return ""
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return "certificate signed by unknown authority"
+	}
+	var hostname x509.HostnameError
+	if errors.As(err, &hostname) {
+		return fmt.Sprintf("certificate is not valid for %s", hostname.Host)
+	}
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) {
+		if invalid.Reason == x509.Expired {
+			return "certificate has expired or is not yet valid"
+		}
+		return "certificate is invalid"
+	}
+	var verification *tls.CertificateVerificationError
+	if errors.As(err, &verification) {
+		return "certificate could not be verified"
+	}
+	var alert tls.AlertError
+	if errors.As(err, &alert) {
+		return "handshake failed: " + strings.TrimPrefix(alert.Error(), "tls: ")
+	}
+	var recordHeader tls.RecordHeaderError
+	if errors.As(err, &recordHeader) {
+		return "server did not respond with TLS"
+	}
+	// alerts sent by the server, such as when it requires a client certificate, are not exported so can only be found
+	// by their message
+	if _, reason, found := strings.Cut(err.Error(), "tls: "); found {
+		return "handshake failed: " + reason
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM will write the block to a file in the directory and return its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Expected no error writing %s, but got %v", name, err)
+	}
+	return path
+}
+
+// newClientCertificate returns a self signed client certificate along with the paths to its pem certificate and key
+func newClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error generating key, but got %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dizzy"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Expected no error creating certificate, but got %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Expected no error parsing certificate, but got %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Expected no error marshalling key, but got %v", err)
+	}
+	return certificate, writePEM(t, dir, "client.pem", "CERTIFICATE", der),
+		writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+// newTLSServer returns a tls server that only accepts connections that the config allows
+func newTLSServer(config func(*tls.Config)) *httptest.Server {
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	mockServer.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	if config != nil {
+		config(mockServer.TLS)
+	}
+	mockServer.StartTLS()
+	return mockServer
+}
+
+func TestRequester_TLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCertificate, clientCert, clientKey := newClientCertificate(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCertificate)
+	requireClientCert := func(config *tls.Config) {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = clientCAs
+	}
+	onlyTLS12 := func(config *tls.Config) {
+		config.MaxVersion = tls.VersionTLS12
+	}
+
+	tests := []struct {
+		name         string
+		server       func(*tls.Config)
+		options      func(mockServer *httptest.Server) TLSOptions
+		wantStatus   int
+		wantTLSError string
+	}{
+		{
+			name:         "should record a self signed certificate as a tls error",
+			options:      func(_ *httptest.Server) TLSOptions { return TLSOptions{} },
+			wantTLSError: "certificate signed by unknown authority",
+		},
+		{
+			name:       "should skip verification when insecure",
+			options:    func(_ *httptest.Server) TLSOptions { return TLSOptions{Insecure: true} },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should trust certificates in the CA bundle",
+			options: func(mockServer *httptest.Server) TLSOptions {
+				return TLSOptions{CABundle: writePEM(t, dir, "ca.pem", "CERTIFICATE", mockServer.Certificate().Raw)}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should verify the certificate against the SNI override",
+			options: func(mockServer *httptest.Server) TLSOptions {
+				return TLSOptions{
+					CABundle:   writePEM(t, dir, "ca.pem", "CERTIFICATE", mockServer.Certificate().Raw),
+					ServerName: "example.com",
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should record a certificate that is not valid for the SNI override as a tls error",
+			options: func(mockServer *httptest.Server) TLSOptions {
+				return TLSOptions{
+					CABundle:   writePEM(t, dir, "ca.pem", "CERTIFICATE", mockServer.Certificate().Raw),
+					ServerName: "other.invalid",
+				}
+			},
+			wantTLSError: "certificate is not valid for other.invalid",
+		},
+		{
+			name:   "should send the client certificate to servers that require one",
+			server: requireClientCert,
+			options: func(_ *httptest.Server) TLSOptions {
+				return TLSOptions{Insecure: true, ClientCert: clientCert, ClientKey: clientKey}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "should record a missing client certificate as a tls error",
+			server:       requireClientCert,
+			options:      func(_ *httptest.Server) TLSOptions { return TLSOptions{Insecure: true} },
+			wantTLSError: "handshake failed",
+		},
+		{
+			name:         "should record a server below the minimum version as a tls error",
+			server:       onlyTLS12,
+			options:      func(_ *httptest.Server) TLSOptions { return TLSOptions{Insecure: true, MinVersion: "1.3"} },
+			wantTLSError: "handshake failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := newTLSServer(tt.server)
+			defer mockServer.Close()
+
+			tlsConfig, err := NewTLSConfig(tt.options(mockServer))
+			if err != nil {
+				t.Fatalf("Expected no error creating tls config, but got %v", err)
+			}
+			config := DefaultTransportConfig()
+			config.TLSClientConfig = tlsConfig
+			r := NewRequester(5*time.Second, "GET", nil, "")
+			r.UseTransport(NewTransport(config))
+
+			response, _ := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "admin"})
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, but got %d with error %s", tt.wantStatus, response.StatusCode,
+					response.Error)
+			}
+			if !strings.HasPrefix(response.TLSError, tt.wantTLSError) ||
+				(tt.wantTLSError == "") != (response.TLSError == "") {
+				t.Errorf("Expected tls error %q, but got %q", tt.wantTLSError, response.TLSError)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, clientCert, clientKey := newClientCertificate(t, dir)
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Expected no error writing file, but got %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		options        TLSOptions
+		wantMinVersion uint16
+		wantError      bool
+	}{
+		{name: "should default to TLS 1.2", options: TLSOptions{}, wantMinVersion: tls.VersionTLS12},
+		{name: "should set the minimum version", options: TLSOptions{MinVersion: "1.0"}, wantMinVersion: tls.VersionTLS10},
+		{name: "should reject an unknown version", options: TLSOptions{MinVersion: "2.0"}, wantError: true},
+		{name: "should reject a missing CA bundle", options: TLSOptions{CABundle: filepath.Join(dir, "missing.pem")},
+			wantError: true},
+		{name: "should reject a CA bundle without certificates", options: TLSOptions{CABundle: empty}, wantError: true},
+		{name: "should reject a client key without a certificate", options: TLSOptions{ClientKey: clientKey},
+			wantError: true},
+		{name: "should reject a client certificate without its key", options: TLSOptions{ClientCert: clientCert},
+			wantError: true},
+		{name: "should load a client certificate and key", options: TLSOptions{ClientCert: clientCert,
+			ClientKey: clientKey}, wantMinVersion: tls.VersionTLS12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewTLSConfig(tt.options)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Expected error to be %v, but got %v", tt.wantError, err)
+			}
+			if tt.wantError {
+				return
+			}
+			if config.MinVersion != tt.wantMinVersion {
+				t.Errorf("Expected minimum version %x, but got %x", tt.wantMinVersion, config.MinVersion)
+			}
+		})
+	}
+}
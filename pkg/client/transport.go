@@ -0,0 +1,115 @@
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TransportConfig tunes the connection pool of the transport that every request made by a requester is sent through
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is how many connections are kept open and reused for each host, this should be at least the
+	// number of workers so that every worker can keep its own connection alive between requests
+	MaxIdleConnsPerHost int
+	// DialTimeout is how long to wait for a connection to be established
+	DialTimeout time.Duration
+	// KeepAlive is how often keep alive probes are sent on open connections
+	KeepAlive time.Duration
+	// IdleConnTimeout is how long an unused connection is kept open before it is closed
+	IdleConnTimeout time.Duration
+	// Protocol is the version of http that requests are sent with
+	Protocol Protocol
+	// TLSClientConfig is used for connections made over TLS, nil uses the default config
+	TLSClientConfig *tls.Config
+	// Proxy is the http or socks5 proxy that requests are sent through, nil uses the proxy from the environment
+	Proxy *url.URL
+}
+
+// DefaultTransportConfig returns the config used by a requester unless it is given its own transport
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConnsPerHost: 100,
+		DialTimeout:         5 * time.Second,
+		KeepAlive:           30 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		Protocol:            ProtocolAuto,
+	}
+}
+
+// defaultTransport is shared by every requester that has not been given its own transport
+var defaultTransport = NewTransport(DefaultTransportConfig())
+
+// NewTransport returns a transport tuned for making many requests at once to the same few hosts, unlike the default
+// http transport which only keeps two idle connections per host and so constantly opens new connections under load.
+// The protocol decides whether requests are sent over HTTP/1.1, HTTP/2, HTTP/3 or whichever the server offers.
+func NewTransport(config TransportConfig) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != nil {
+		if !config.Protocol.SupportsProxy() {
+			log.Warnf("Cannot send %s requests through a proxy, they will be sent directly to the target", config.Protocol)
+		}
+		proxy = http.ProxyURL(config.Proxy)
+	}
+	switch config.Protocol {
+	case ProtocolHTTP2:
+		return newHTTP2Transport(config, dialer, false)
+	case ProtocolH2C:
+		return newHTTP2Transport(config, dialer, true)
+	case ProtocolHTTP3:
+		return newHTTP3Transport(config)
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       config.TLSClientConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.DialTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if config.Protocol == ProtocolHTTP1 {
+		// a non nil map stops the transport from upgrading to HTTP/2 when the server offers it
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if config.Protocol == ProtocolAltSvc {
+		return newAltSvcTransport(config, transport)
+	}
+	return transport
+}
+
+// UseTransport will send every request made by the requester through the given transport, the same client is reused
+// for every request so that connections are pooled
+func (r *Requester) UseTransport(transport http.RoundTripper) {
+	r.client = newHTTPClient(r.Timeout, transport)
+}
+
+// httpClient returns the client every request is sent with, a requester that was not created with NewRequester and has
+// not been given a transport uses the shared default transport
+func (r *Requester) httpClient() *http.Client {
+	if r.client != nil {
+		return r.client
+	}
+	return newHTTPClient(r.Timeout, defaultTransport)
+}
+
+// newHTTPClient returns a client that sends requests through the transport without following redirects, as redirects
+// are part of what is being looked for
+func newHTTPClient(timeout time.Duration, transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// workers is how many requests are made at once by the tests and benchmarks, the same as the default worker count
+const workers = 40
+
+// newCountingServer returns a server that counts how many connections have been opened to it
+func newCountingServer(connections *atomic.Int32) *httptest.Server {
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	mockServer.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	mockServer.Start()
+	return mockServer
+}
+
+// reuseCounter wraps a transport to count how many requests were sent over a connection that had already been used,
+// which unlike counting connections does not depend on how the requests happen to be scheduled
+type reuseCounter struct {
+	transport http.RoundTripper
+	requests  atomic.Int32
+	reused    atomic.Int32
+}
+
+func (c *reuseCounter) RoundTrip(request *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				c.reused.Add(1)
+			}
+		},
+	}
+	return c.transport.RoundTrip(request.WithContext(httptrace.WithClientTrace(request.Context(), trace)))
+}
+
+func TestRequester_UseTransport(t *testing.T) {
+	t.Run("should reuse a connection for each worker", func(t *testing.T) {
+		var connections atomic.Int32
+		mockServer := newCountingServer(&connections)
+		defer mockServer.Close()
+
+		r := NewRequester(5*time.Second, "GET", nil, "")
+		config := DefaultTransportConfig()
+		config.MaxIdleConnsPerHost = workers
+		counter := &reuseCounter{transport: NewTransport(config)}
+		r.UseTransport(counter)
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 20 {
+					if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"}); err != nil {
+						t.Errorf("Expected no error, but got %v", err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		// a few extra connections can be opened while every idle one is briefly in use, but almost every request
+		// should go over a connection that was kept open
+		requests, reused := counter.requests.Load(), counter.reused.Load()
+		if reused < requests*3/4 {
+			t.Errorf("Expected most of the %d requests to reuse a connection, but only %d did with %d connections opened",
+				requests, reused, connections.Load())
+		}
+	})
+}
+
+// benchmarkTransport makes requests from as many goroutines as workers through a requester using the transport, the
+// number of connections opened is reported alongside the time taken. Run with -cpu 4 or more to see the difference, the
+// default transport only keeps two idle connections so opens thousands of new connections under load.
+func benchmarkTransport(b *testing.B, transport http.RoundTripper) {
+	var connections atomic.Int32
+	mockServer := newCountingServer(&connections)
+	defer mockServer.Close()
+
+	r := NewRequester(5*time.Second, "GET", nil, "")
+	r.UseTransport(transport)
+
+	b.ResetTimer()
+	b.SetParallelism(workers)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := r.MakeRequest(Request{URL: mockServer.URL, Subdomain: "word"}); err != nil {
+				b.Errorf("Expected no error, but got %v", err)
+			}
+		}
+	})
+	b.ReportMetric(float64(connections.Load()), "conns")
+}
+
+func BenchmarkMakeRequest_DefaultTransport(b *testing.B) {
+	benchmarkTransport(b, http.DefaultTransport.(*http.Transport).Clone())
+}
+
+func BenchmarkMakeRequest_TunedTransport(b *testing.B) {
+	config := DefaultTransportConfig()
+	config.MaxIdleConnsPerHost = workers * 8
+	benchmarkTransport(b, NewTransport(config))
+}
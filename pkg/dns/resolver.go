@@ -0,0 +1,132 @@
+// Package dns provides resolution of subdomains against configurable resolvers without making any http requests
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Records are the records that a host resolved to, the cname is empty when the host is not an alias
+type Records struct {
+	A     []string `json:"a,omitempty"`
+	AAAA  []string `json:"aaaa,omitempty"`
+	CNAME string   `json:"cname,omitempty"`
+}
+
+// Empty returns whether the host did not resolve to anything
+func (r *Records) Empty() bool {
+	return len(r.A) == 0 && len(r.AAAA) == 0 && r.CNAME == ""
+}
+
+// Addresses returns every address that the host resolved to
+func (r *Records) Addresses() []string {
+	return append(slices.Clone(r.A), r.AAAA...)
+}
+
+// Resolver resolves hosts using the given name servers in turn, or the system resolver if none are given
+type Resolver struct {
+	Servers  []string
+	Timeout  time.Duration
+	resolver *net.Resolver
+	next     atomic.Uint64
+}
+
+// NewResolver returns a resolver that will query the given name servers, a name server without a port will be queried
+// on port 53
+func NewResolver(servers []string, timeout time.Duration) *Resolver {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	r := &Resolver{
+		Timeout: timeout,
+	}
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		r.Servers = append(r.Servers, server)
+	}
+
+	if len(r.Servers) == 0 {
+		r.resolver = net.DefaultResolver
+		return r
+	}
+	r.resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: r.Timeout}
+			return dialer.DialContext(ctx, network, r.server())
+		},
+	}
+	return r
+}
+
+// server returns the next name server to query, spreading queries across all of them
+func (r *Resolver) server() string {
+	return r.Servers[(r.next.Add(1)-1)%uint64(len(r.Servers))]
+}
+
+// Resolve will look up the a, aaaa and cname records for the host, an error is only returned when the host does not
+// exist or every lookup failed
+func (r *Resolver) Resolve(ctx context.Context, host string) (Records, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var records Records
+	var errs []error
+
+	cname, err := r.resolver.LookupCNAME(ctx, host)
+	if err != nil {
+		if isNotFound(err) {
+			return records, fmt.Errorf("%s does not exist: %w", host, err)
+		}
+		errs = append(errs, err)
+	} else if canonical := strings.TrimSuffix(cname, "."); !strings.EqualFold(canonical, strings.TrimSuffix(host, ".")) {
+		records.CNAME = canonical
+	}
+
+	for _, lookup := range []struct {
+		network string
+		into    *[]string
+	}{
+		{"ip4", &records.A},
+		{"ip6", &records.AAAA},
+	} {
+		ips, err := r.resolver.LookupIP(ctx, lookup.network, host)
+		if err != nil {
+			if !isNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for _, ip := range ips {
+			*lookup.into = append(*lookup.into, ip.String())
+		}
+		slices.Sort(*lookup.into)
+	}
+
+	if records.Empty() {
+		if len(errs) > 0 {
+			return records, fmt.Errorf("error resolving %s: %w", host, errors.Join(errs...))
+		}
+		return records, fmt.Errorf("%s has no records", host)
+	}
+	return records, nil
+}
+
+// isNotFound returns whether the error is because the host does not exist, or has no records of the type requested
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,213 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// zoneEntry is a record served by the stub server, a cname is followed to its target when a or aaaa is queried
+type zoneEntry struct {
+	cname string
+	a     []string
+	aaaa  []string
+}
+
+// startStubServer starts a dns server on a local udp port that answers from the zone, names in the zone are fully
+// qualified and a name starting with * answers for any subdomain
+func startStubServer(t *testing.T, zone map[string]zoneEntry) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start stub dns server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			response, err := answer(&query, zone).Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// lookupZone returns the entry for the name, falling back to a wildcard entry for the parent domain
+func lookupZone(zone map[string]zoneEntry, name string) (zoneEntry, bool) {
+	if entry, ok := zone[name]; ok {
+		return entry, true
+	}
+	if _, parent, ok := strings.Cut(name, "."); ok {
+		entry, ok := zone["*."+parent]
+		return entry, ok
+	}
+	return zoneEntry{}, false
+}
+
+// answer builds the response to the query from the zone
+func answer(query *dnsmessage.Message, zone map[string]zoneEntry) *dnsmessage.Message {
+	question := query.Questions[0]
+	response := &dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true, RecursionAvailable: true},
+		Questions: query.Questions,
+	}
+
+	name := question.Name.String()
+	entry, ok := lookupZone(zone, name)
+	if !ok {
+		response.RCode = dnsmessage.RCodeNameError
+		return response
+	}
+
+	header := func(name string, recordType dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: recordType, Class: dnsmessage.ClassINET, TTL: 60}
+	}
+	if entry.cname != "" {
+		response.Answers = append(response.Answers, dnsmessage.Resource{
+			Header: header(name, dnsmessage.TypeCNAME),
+			Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(entry.cname)},
+		})
+		if question.Type == dnsmessage.TypeCNAME {
+			return response
+		}
+		name = entry.cname
+		entry = zone[entry.cname]
+	}
+
+	switch question.Type {
+	case dnsmessage.TypeA:
+		for _, address := range entry.a {
+			var ip [4]byte
+			copy(ip[:], net.ParseIP(address).To4())
+			response.Answers = append(response.Answers, dnsmessage.Resource{
+				Header: header(name, dnsmessage.TypeA),
+				Body:   &dnsmessage.AResource{A: ip},
+			})
+		}
+	case dnsmessage.TypeAAAA:
+		for _, address := range entry.aaaa {
+			var ip [16]byte
+			copy(ip[:], net.ParseIP(address).To16())
+			response.Answers = append(response.Answers, dnsmessage.Resource{
+				Header: header(name, dnsmessage.TypeAAAA),
+				Body:   &dnsmessage.AAAAResource{AAAA: ip},
+			})
+		}
+	}
+	return response
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	server := startStubServer(t, map[string]zoneEntry{
+		"www.example.test.":      {a: []string{"10.0.0.2", "10.0.0.1"}, aaaa: []string{"fd00::1"}},
+		"blog.example.test.":     {cname: "hosting.provider.test."},
+		"hosting.provider.test.": {a: []string{"10.1.1.1"}},
+	})
+	resolver := NewResolver([]string{server}, 2*time.Second)
+
+	tests := []struct {
+		name      string
+		host      string
+		want      Records
+		wantError bool
+	}{
+		{
+			name: "should return the a and aaaa records sorted",
+			host: "www.example.test",
+			want: Records{A: []string{"10.0.0.1", "10.0.0.2"}, AAAA: []string{"fd00::1"}},
+		},
+		{
+			name: "should return the cname and the addresses of its target",
+			host: "blog.example.test",
+			want: Records{A: []string{"10.1.1.1"}, CNAME: "hosting.provider.test"},
+		},
+		{
+			name:      "should return an error when the host does not exist",
+			host:      "nope.example.test",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := resolver.Resolve(context.Background(), tt.host)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error, got records: %+v", records)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if strings.Join(records.A, ",") != strings.Join(tt.want.A, ",") ||
+				strings.Join(records.AAAA, ",") != strings.Join(tt.want.AAAA, ",") ||
+				records.CNAME != tt.want.CNAME {
+				t.Errorf("Resolve(%q) = %+v; want %+v", tt.host, records, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_DetectWildcard(t *testing.T) {
+	server := startStubServer(t, map[string]zoneEntry{
+		"*.wild.test.":     {a: []string{"10.9.9.9"}},
+		"admin.wild.test.": {a: []string{"10.0.0.5"}},
+		"www.tame.test.":   {a: []string{"10.0.0.6"}},
+	})
+	resolver := NewResolver([]string{server}, 2*time.Second)
+
+	t.Run("should detect a wildcard and only match records pointing at it", func(t *testing.T) {
+		wildcard := resolver.DetectWildcard(context.Background(), "wild.test")
+		if !wildcard.Detected() {
+			t.Fatalf("Expected a wildcard to be detected")
+		}
+
+		records, _ := resolver.Resolve(context.Background(), "anything.wild.test")
+		if !wildcard.Match(&records) {
+			t.Errorf("Expected %+v to match the wildcard", records)
+		}
+
+		records, _ = resolver.Resolve(context.Background(), "admin.wild.test")
+		if wildcard.Match(&records) {
+			t.Errorf("Expected %+v not to match the wildcard", records)
+		}
+	})
+
+	t.Run("should not detect a wildcard when random subdomains do not resolve", func(t *testing.T) {
+		wildcard := resolver.DetectWildcard(context.Background(), "tame.test")
+		if wildcard.Detected() {
+			t.Errorf("Expected no wildcard to be detected, got %+v", wildcard)
+		}
+	})
+}
+
+func TestNewResolver(t *testing.T) {
+	t.Run("should default the port of each name server to 53", func(t *testing.T) {
+		resolver := NewResolver([]string{"1.1.1.1", " 8.8.8.8:5353", ""}, 0)
+
+		if len(resolver.Servers) != 2 || resolver.Servers[0] != "1.1.1.1:53" || resolver.Servers[1] != "8.8.8.8:5353" {
+			t.Errorf("Servers = %v; want [1.1.1.1:53 8.8.8.8:5353]", resolver.Servers)
+		}
+		if resolver.server() != "1.1.1.1:53" || resolver.server() != "8.8.8.8:5353" {
+			t.Errorf("Expected name servers to be used in turn")
+		}
+	})
+}
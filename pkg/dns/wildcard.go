@@ -0,0 +1,64 @@
+package dns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ch55secake/dizzy/internal/random"
+)
+
+// Wildcard holds every address and cname that random subdomains resolved to, a domain with a wildcard record will
+// resolve any subdomain so results that only resolve to the same records are not interesting
+type Wildcard struct {
+	Addresses map[string]struct{}
+	CNAMEs    map[string]struct{}
+}
+
+// Detected returns whether any of the random subdomains resolved
+func (w *Wildcard) Detected() bool {
+	return len(w.Addresses) > 0 || len(w.CNAMEs) > 0
+}
+
+// Match returns whether the records only point at the wildcard, either by cname or with every address
+func (w *Wildcard) Match(records *Records) bool {
+	if !w.Detected() {
+		return false
+	}
+	if records.CNAME != "" {
+		if _, ok := w.CNAMEs[strings.ToLower(records.CNAME)]; ok {
+			return true
+		}
+	}
+	addresses := records.Addresses()
+	if len(addresses) == 0 {
+		return false
+	}
+	for _, address := range addresses {
+		if _, ok := w.Addresses[address]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectWildcard will resolve a handful of random subdomains of the domain, if any of them resolve the domain has a
+// wildcard record
+func (r *Resolver) DetectWildcard(ctx context.Context, domain string) *Wildcard {
+	wildcard := &Wildcard{
+		Addresses: make(map[string]struct{}),
+		CNAMEs:    make(map[string]struct{}),
+	}
+	for range 3 {
+		records, err := r.Resolve(ctx, random.Label(16)+"."+domain)
+		if err != nil {
+			continue
+		}
+		for _, address := range records.Addresses() {
+			wildcard.Addresses[address] = struct{}{}
+		}
+		if records.CNAME != "" {
+			wildcard.CNAMEs[strings.ToLower(records.CNAME)] = struct{}{}
+		}
+	}
+	return wildcard
+}
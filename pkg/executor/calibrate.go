@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ch55secake/dizzy/internal/random"
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/filter"
+	"github.com/ch55secake/dizzy/pkg/job"
+	log "github.com/sirupsen/logrus"
+)
+
+// calibrate will request a handful of random paths, or subdomains, that should not exist under the given url and add
+// the responses to the calibration, so that any result that looks the same can be filtered out as a wildcard or soft
+// 404. When multiple wordlists are bound to keywords every keyword is replaced with the same random word. Probes that
+// fail are skipped and an error is only returned when none were answered, unless scanning subdomains where a random
+// label not resolving just means there is no wildcard. Each probe waits on the throttle, which can be nil, like any
+// other request.
+func calibrate(r *client.Requester, throttle *job.Throttle, url string, mode client.Mode, keywords []string,
+	calibration *filter.Calibration) error {
+	words := probeWords(mode)
+	probes := make([]client.Response, 0, len(words))
+	var lastErr error
+	for _, word := range words {
+		request := client.Request{
+			URL:       url,
+			Subdomain: word,
+			Mode:      mode,
+		}
+		if len(keywords) > 0 {
+			request.Payload = make(map[string]string, len(keywords))
+			for _, keyword := range keywords {
+				request.Payload[keyword] = word
+			}
+		}
+		throttle.Wait()
+		response, err := r.MakeRequest(request)
+		if err != nil {
+			log.Debugf("calibration probe of %s failed: %v", response.URL, err)
+			lastErr = err
+			continue
+		}
+		probes = append(probes, response)
+	}
+	if len(probes) == 0 {
+		if mode == client.ModeSubdomain {
+			log.Debugf("no calibration probes of %s resolved, so it does not have a wildcard", url)
+			return nil
+		}
+		return fmt.Errorf("error calibrating against %s, none of the probes were answered: %w", url, lastErr)
+	}
+
+	baselines := calibration.Add(probes)
+	log.Debugf("calibrated %s with %d baselines from %d probes", url, baselines, len(probes))
+	return nil
+}
+
+// newCalibration returns a calibration that compares responses against the probes sent to the same directory, parent
+// domain or for the whole scan depending on where the word is placed
+func newCalibration(mode client.Mode) *filter.Calibration {
+	switch mode {
+	case client.ModeSubdomain:
+		return filter.NewSubdomainCalibration()
+	case client.ModeKeyword:
+		return filter.NewTemplateCalibration()
+	default:
+		return filter.NewCalibration()
+	}
+}
+
+// probeWords returns random words of differing lengths, when scanning paths this includes one that looks like a file
+// and one that looks like a directory, as targets often respond differently to each
+func probeWords(mode client.Mode) []string {
+	if mode == client.ModeSubdomain {
+		return []string{random.Label(8), random.Label(16), random.Label(32)}
+	}
+	return []string{
+		random.Label(8),
+		random.Label(16),
+		random.Label(32),
+		random.Label(12) + ".html",
+		random.Label(12) + "/",
+	}
+}
@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/filter"
+)
+
+func Test_calibrate(t *testing.T) {
+	t.Run("should filter out responses that look like the wildcard response", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			if r.URL.Path == "/admin" {
+				_, _ = w.Write([]byte("welcome to the admin panel, please log in"))
+				return
+			}
+			_, _ = w.Write([]byte("nothing to see here"))
+		}))
+		defer mockServer.Close()
+
+		r := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		calibration := filter.NewCalibration()
+		err := calibrate(r, nil, mockServer.URL, client.ModeDirectory, nil, calibration)
+		if err != nil {
+			t.Fatalf("calibrate returned an unexpected error: %v", err)
+		}
+
+		wildcard, _ := r.MakeRequest(client.Request{URL: mockServer.URL, Subdomain: "banana"})
+		if !calibration.Match(&wildcard) {
+			t.Errorf("Expected wildcard response to be filtered out")
+		}
+
+		admin, _ := r.MakeRequest(client.Request{URL: mockServer.URL, Subdomain: "admin"})
+		if calibration.Match(&admin) {
+			t.Errorf("Expected admin response to be kept")
+		}
+	})
+
+	t.Run("should calibrate from the probes that were answered when one fails", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				_ = conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("nothing to see here"))
+		}))
+		defer mockServer.Close()
+
+		r := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		calibration := filter.NewCalibration()
+		err := calibrate(r, nil, mockServer.URL, client.ModeDirectory, nil, calibration)
+		if err != nil {
+			t.Fatalf("calibrate returned an unexpected error: %v", err)
+		}
+
+		wildcard, _ := r.MakeRequest(client.Request{URL: mockServer.URL, Subdomain: "banana"})
+		if !calibration.Match(&wildcard) {
+			t.Errorf("Expected wildcard response to be filtered out")
+		}
+	})
+
+	t.Run("should return an error when none of the probes were answered", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.NotFoundHandler())
+		mockServer.Close()
+
+		r := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		err := calibrate(r, nil, mockServer.URL, client.ModeDirectory, nil, filter.NewCalibration())
+		if err == nil {
+			t.Errorf("Expected an error when the target cannot be reached")
+		}
+	})
+
+	t.Run("should not return an error when no random subdomain resolves", func(t *testing.T) {
+		r := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		calibration := filter.NewSubdomainCalibration()
+		err := calibrate(r, nil, "http://dizzy.invalid", client.ModeSubdomain, nil, calibration)
+		if err != nil {
+			t.Errorf("Expected no error for a domain without a wildcard, but got %v", err)
+		}
+		if calibration.Calibrated("http://admin.dizzy.invalid") {
+			t.Errorf("Expected no baselines for a domain without a wildcard")
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/filter"
+	"github.com/ch55secake/dizzy/pkg/job"
+	"github.com/ch55secake/dizzy/pkg/output"
+	log "github.com/sirupsen/logrus"
+)
+
+// collector handles every result published by the dispatcher, it filters them, recurses into any directories that are
+// discovered and, when they are going to be written to an output file, keeps hold of the results until the scan has
+// finished. It is only ever used from the dispatcher's Collect so does not need to be safe for concurrent use.
+type collector struct {
+	dispatcher  *job.Dispatcher
+	requester   *client.Requester
+	filters     *filter.Set
+	calibration *filter.Calibration
+	source      jobSource
+	replayer    *replayer
+	columns     []output.Column
+	mode        client.Mode
+	target      string
+	depth       int
+	scanned     map[string]struct{}
+	jobs        int
+	tlsErrors   int
+	tlsError    string
+	keep        bool
+	results     []client.Response
+}
+
+// jobSource generates the jobs for a scan of the given url at the given depth, the jobs are sent on the returned channel
+// as the wordlists are read and it is closed once every job has been generated
+type jobSource func(url string, depth int) <-chan *job.Job
+
+// newCollector returns a collector for a scan of the url in the context, which will recurse up to the depth in the
+// context using jobs generated by the same source as the scan. Calibration can be nil if the scan is not auto
+// calibrating. Only directories can be recursed into so the depth is ignored unless scanning paths.
+func newCollector(dispatcher *job.Dispatcher, requester *client.Requester, filters *filter.Set,
+	calibration *filter.Calibration, source jobSource, columns []output.Column, ctx ExecutionContext) *collector {
+	depth := ctx.Depth
+	if ctx.Mode != client.ModeDirectory && ctx.Mode != "" {
+		depth = 0
+	}
+	return &collector{
+		dispatcher:  dispatcher,
+		requester:   requester,
+		filters:     filters,
+		calibration: calibration,
+		source:      source,
+		columns:     columns,
+		mode:        ctx.Mode,
+		target:      strings.TrimSuffix(ctx.URL, "/"),
+		depth:       depth,
+		scanned:     map[string]struct{}{strings.TrimSuffix(ctx.URL, "/"): {}},
+		keep:        ctx.OutputFile != "",
+	}
+}
+
+// handle will print each response that is kept by the filters, recursing into it if it is a directory. Kept responses
+// are also replayed through the replay proxy if there is one, and held onto if there is an output file to write.
+func (c *collector) handle(result job.Result) {
+	c.jobs++
+	if result.Err != nil {
+		log.Debugf("job %d returned an error: %v", result.Job.ID, result.Err)
+	}
+
+	response := result.Response
+	if response.TLSError != "" {
+		c.tlsErrors++
+		if c.tlsError == "" {
+			c.tlsError = response.TLSError
+		}
+	}
+	if !c.filters.Keep(&response) {
+		return
+	}
+	// the body is only needed for matching, so drop it rather than holding every body in memory
+	response.Body = ""
+	response.Path = relativePath(c.target, &response)
+
+	output.PrintResult(c.columns, &response)
+	if c.keep {
+		c.results = append(c.results, response)
+	}
+	c.replayer.replay(result.Job.Request)
+
+	if result.Job.Depth < c.depth && isDirectory(&response) {
+		c.recurse(strings.TrimSuffix(response.URL, "/"), result.Job.Depth+1)
+	}
+}
+
+// recurse will submit every job from the wordlists again under the given directory, unless it has already been
+// scanned. When auto calibrating the directory is calibrated first, as targets often respond differently per directory.
+func (c *collector) recurse(directory string, depth int) {
+	if _, ok := c.scanned[directory]; ok {
+		return
+	}
+	c.scanned[directory] = struct{}{}
+
+	output.PrintMagentaMessage(fmt.Sprintf("Recursing into: %v", directory), true)
+	if c.calibration == nil {
+		c.dispatcher.SubmitStream(c.source(directory, depth))
+		return
+	}
+	c.dispatcher.SubmitStream(c.calibrated(directory, depth))
+}
+
+// calibrated returns the jobs for the directory once it has been calibrated. The probes are sent from the goroutine
+// that streams the jobs rather than from Collect, as the workers would all stall waiting for their results to be
+// collected.
+func (c *collector) calibrated(directory string, depth int) <-chan *job.Job {
+	jobs := make(chan *job.Job)
+	go func() {
+		defer close(jobs)
+		err := calibrate(c.requester, c.dispatcher.Throttle, directory, c.mode, nil, c.calibration)
+		if err != nil {
+			log.Warnf("auto calibration failed for %s, continuing without it: %v", directory, err)
+		}
+		for j := range c.source(directory, depth) {
+			jobs <- j
+		}
+	}()
+	return jobs
+}
+
+// relativePath returns the path that was requested relative to the target, such as admin/config for a result found
+// whilst recursing into admin. A url that is not under the target, such as in subdomain mode, falls back to the word.
+func relativePath(target string, response *client.Response) string {
+	path, ok := strings.CutPrefix(response.URL, target+"/")
+	if !ok || path == "" {
+		return response.Subdomain
+	}
+	return path
+}
+
+// isDirectory returns whether the response looks like a directory, either a redirect to the same path with a trailing
+// slash or a successful or forbidden response to a path that already had a trailing slash
+func isDirectory(response *client.Response) bool {
+	switch response.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		requested, err := url.Parse(response.URL)
+		if err != nil {
+			return false
+		}
+		location, err := requested.Parse(response.Location)
+		if err != nil {
+			return false
+		}
+		return location.Host == requested.Host && location.Path == requested.Path+"/"
+	case http.StatusOK, http.StatusForbidden:
+		requested, err := url.Parse(response.URL)
+		if err != nil {
+			return false
+		}
+		return strings.HasSuffix(requested.Path, "/")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,286 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/filter"
+	"github.com/ch55secake/dizzy/pkg/job"
+	"github.com/ch55secake/dizzy/pkg/output"
+)
+
+func Test_isDirectory(t *testing.T) {
+	tests := []struct {
+		name     string
+		response client.Response
+		want     bool
+	}{
+		{
+			name:     "should be a directory when redirected to the same path with a trailing slash",
+			response: client.Response{URL: "http://example.com/admin", StatusCode: http.StatusMovedPermanently, Location: "http://example.com/admin/"},
+			want:     true,
+		},
+		{
+			name:     "should be a directory when redirected with a relative location",
+			response: client.Response{URL: "http://example.com/api/v1", StatusCode: http.StatusFound, Location: "/api/v1/"},
+			want:     true,
+		},
+		{
+			name:     "should not be a directory when redirected somewhere else",
+			response: client.Response{URL: "http://example.com/admin", StatusCode: http.StatusFound, Location: "/login"},
+			want:     false,
+		},
+		{
+			name:     "should be a directory when a path with a trailing slash is forbidden",
+			response: client.Response{URL: "http://example.com/admin/", StatusCode: http.StatusForbidden},
+			want:     true,
+		},
+		{
+			name:     "should not be a directory when a path without a trailing slash is ok",
+			response: client.Response{URL: "http://example.com/admin", StatusCode: http.StatusOK},
+			want:     false,
+		},
+		{
+			name:     "should not be a directory when not found",
+			response: client.Response{URL: "http://example.com/admin/", StatusCode: http.StatusNotFound},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDirectory(&tt.response); got != tt.want {
+				t.Errorf("isDirectory() = %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollector_recursion(t *testing.T) {
+	t.Run("should recurse into discovered directories up to the depth without scanning any twice", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/admin", "/admin/admin", "/admin/admin/admin":
+				http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			case "/admin/":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer mockServer.Close()
+
+		requests := []client.Request{
+			{URL: mockServer.URL, Subdomain: "admin"},
+			{URL: mockServer.URL, Subdomain: "admin/"},
+			{URL: mockServer.URL, Subdomain: "banana"},
+		}
+		filters, err := filter.NewSet(filter.Criteria{}, filter.Criteria{Status: "404"})
+		if err != nil {
+			t.Fatalf("failed to create filters: %v", err)
+		}
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := job.NewDispatcher(2, len(requests))
+		for i, request := range requests {
+			dispatcher.Submit(job.NewJob(i, request))
+		}
+		dispatcher.Run(requester)
+
+		ctx := ExecutionContext{
+			URL:        mockServer.URL,
+			Mode:       client.ModeDirectory,
+			Depth:      2,
+			OutputFile: "results.json",
+		}
+		c := newCollector(dispatcher, requester, filters, nil, sliceSource(requests), output.PathColumns, ctx)
+		go dispatcher.Collect(c.handle)
+		dispatcher.Wait()
+
+		// the root, /admin and /admin/admin are scanned, /admin/admin/admin is found but is past the depth
+		if c.jobs != 9 {
+			t.Errorf("Expected 9 jobs to be run, but got %d", c.jobs)
+		}
+		if len(c.scanned) != 3 {
+			t.Errorf("Expected 3 directories to be scanned, but got %d", len(c.scanned))
+		}
+		// admin and admin/ are found in the root, only admin is found in the other two
+		if len(c.results) != 4 {
+			t.Errorf("Expected 4 results, but got %d", len(c.results))
+		}
+		// the results found whilst recursing are shown under the directory they were found in
+		var paths []string
+		for _, result := range c.results {
+			paths = append(paths, result.Path)
+		}
+		slices.Sort(paths)
+		if want := []string{"admin", "admin/", "admin/admin", "admin/admin/admin"}; !slices.Equal(paths, want) {
+			t.Errorf("Expected paths %v, but got %v", want, paths)
+		}
+	})
+}
+
+func TestCollector_calibrated(t *testing.T) {
+	t.Run("should calibrate each directory before streaming its jobs", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/admin":
+				http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer mockServer.Close()
+
+		requests := []client.Request{{URL: mockServer.URL, Subdomain: "admin"}}
+		filters, err := filter.NewSet(filter.Criteria{}, filter.Criteria{Status: "404"})
+		if err != nil {
+			t.Fatalf("failed to create filters: %v", err)
+		}
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := job.NewDispatcher(1, len(requests))
+		dispatcher.Throttle = job.NewThrottle(0, time.Millisecond, 0)
+		for i, request := range requests {
+			dispatcher.Submit(job.NewJob(i, request))
+		}
+		dispatcher.Run(requester)
+
+		ctx := ExecutionContext{URL: mockServer.URL, Mode: client.ModeDirectory, Depth: 1}
+		calibration := filter.NewCalibration()
+		c := newCollector(dispatcher, requester, filters, calibration, sliceSource(requests), output.PathColumns, ctx)
+		go dispatcher.Collect(c.handle)
+		dispatcher.Wait()
+
+		if !calibration.Calibrated(mockServer.URL + "/admin/banana") {
+			t.Errorf("Expected %s/admin to be calibrated", mockServer.URL)
+		}
+		// the root and /admin are both scanned
+		if c.jobs != 2 {
+			t.Errorf("Expected 2 jobs to be run, but got %d", c.jobs)
+		}
+	})
+}
+
+func TestCollector_subdomain(t *testing.T) {
+	t.Run("should drop subdomains that do not resolve", func(t *testing.T) {
+		requests := []client.Request{
+			{URL: "http://dizzy.invalid", Subdomain: "admin", Mode: client.ModeSubdomain},
+		}
+		ctx := ExecutionContext{URL: "http://dizzy.invalid", Mode: client.ModeSubdomain, OutputFile: "results.json"}
+		filters, err := newFilterSet(ctx)
+		if err != nil {
+			t.Fatalf("failed to create filters: %v", err)
+		}
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := job.NewDispatcher(1, len(requests))
+		for i, request := range requests {
+			dispatcher.Submit(job.NewJob(i, request))
+		}
+		dispatcher.Run(requester)
+
+		c := newCollector(dispatcher, requester, filters, nil, sliceSource(requests), output.SubdomainColumns, ctx)
+		go dispatcher.Collect(c.handle)
+		dispatcher.Wait()
+
+		if c.jobs != 1 {
+			t.Errorf("Expected 1 job to be run, but got %d", c.jobs)
+		}
+		if len(c.results) != 0 {
+			t.Errorf("Expected no results for a host that does not resolve, but got %v", c.results)
+		}
+	})
+}
+
+func TestCollector_replay(t *testing.T) {
+	t.Run("should only replay matched requests through the replay proxy", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/admin" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockServer.Close()
+		var mu sync.Mutex
+		var replayed []string
+		replayProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			replayed = append(replayed, r.URL.String())
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer replayProxy.Close()
+
+		requests := []client.Request{
+			{URL: mockServer.URL, Subdomain: "admin"},
+			{URL: mockServer.URL, Subdomain: "banana"},
+			{URL: mockServer.URL, Subdomain: "apple"},
+		}
+		filters, err := filter.NewSet(filter.Criteria{}, filter.Criteria{Status: "404"})
+		if err != nil {
+			t.Fatalf("failed to create filters: %v", err)
+		}
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+		proxy, _ := url.Parse(replayProxy.URL)
+		config := client.DefaultTransportConfig()
+		config.Proxy = proxy
+		replayRequester := client.NewRequester(1*time.Second, "GET", nil, "")
+		replayRequester.UseTransport(client.NewTransport(config))
+
+		dispatcher := job.NewDispatcher(2, len(requests))
+		for i, request := range requests {
+			dispatcher.Submit(job.NewJob(i, request))
+		}
+		dispatcher.Run(requester)
+
+		ctx := ExecutionContext{URL: mockServer.URL, Mode: client.ModeDirectory}
+		c := newCollector(dispatcher, requester, filters, nil, sliceSource(requests), output.PathColumns, ctx)
+		c.replayer = newReplayer(replayRequester)
+		go dispatcher.Collect(c.handle)
+		dispatcher.Wait()
+		c.replayer.close()
+
+		if len(replayed) != 1 || replayed[0] != mockServer.URL+"/admin" {
+			t.Errorf("Expected only %s/admin to be replayed, but got %v", mockServer.URL, replayed)
+		}
+		// there is no output file to write them to, so the results should not be held in memory
+		if len(c.results) != 0 {
+			t.Errorf("Expected no results to be kept without an output file, but got %d", len(c.results))
+		}
+	})
+}
+
+// sliceSource returns a job source that generates a job for each of the requests under the given url
+func sliceSource(requests []client.Request) jobSource {
+	return func(url string, depth int) <-chan *job.Job {
+		jobs := make(chan *job.Job, len(requests))
+		for i, request := range requests {
+			request.URL = url
+			j := job.NewJob(i, request)
+			j.Depth = depth
+			jobs <- j
+		}
+		close(jobs)
+		return jobs
+	}
+}
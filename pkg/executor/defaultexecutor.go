@@ -2,18 +2,32 @@
 package executor
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/ch55secake/dizzy/pkg/output"
-	"math"
 	"math/rand"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/filter"
 	"github.com/ch55secake/dizzy/pkg/input"
 	"github.com/ch55secake/dizzy/pkg/job"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// DefaultWorkerCount is how many requests are made at once unless told otherwise
+	DefaultWorkerCount = 40
+	// MaxWorkerCount caps how many requests can be made at once, however large the wordlist or requested worker count
+	MaxWorkerCount = 1000
+	// jobsPerWorker is how many jobs are buffered for each worker, wordlists are only read as fast as jobs leave the queue
+	jobsPerWorker = 2
+)
+
 // ExecutionContext contains important information needed for execution as in where files are coming from
 type ExecutionContext struct {
 	Filepath          string
@@ -23,51 +37,320 @@ type ExecutionContext struct {
 	Method            string
 	Headers           map[string]string
 	OnlyOutputFailure bool
+	OutputFile        string
+	OutputFormat      output.Format
+	Matchers          filter.Criteria
+	Filters           filter.Criteria
+	AutoCalibrate     bool
+	Depth             int
+	Mode              client.Mode
+	Resolvers         []string
+	ProbeHTTP         bool
+	Body              string
+	Wordlists         []input.Binding
+	PayloadMode       input.PayloadMode
+	WordlistOptions   input.Options
+	Rate              float64
+	Delay             time.Duration
+	Jitter            time.Duration
+	NoBackoff         bool
+	Retry             client.RetryPolicy
+	Threads           int
+	Protocol          client.Protocol
+	Proxy             *url.URL
+	ReplayProxy       *url.URL
+	TLSConfig         *tls.Config
 }
 
-// DefaultExecutor is the default executor for any given job
+// DefaultExecutor is the default executor for any given job, the worker count is how many requests are made at once and
+// the queue size is how many jobs are buffered for the workers. Either can be left as zero to use the defaults.
 type DefaultExecutor struct {
-	job.Dispatcher
 	WorkerCount int
 	QueueSize   int
 }
 
-// Execute will use the dispatcher provided on the struct and then kick off all jobs that are ready to be dispatched
-// Will also rely on the queue size and worker count provided when using the dispatcher to execute any jobs
+// NewDefaultExecutor returns an executor with the given number of workers, zero uses the default worker count and
+// anything above the max worker count is capped
+func NewDefaultExecutor(workers int) *DefaultExecutor {
+	e := &DefaultExecutor{WorkerCount: workers}
+	if workers > MaxWorkerCount {
+		log.Warnf("cannot use %d workers, using the max of %d instead", workers, MaxWorkerCount)
+	}
+	e.WorkerCount = e.workers()
+	e.QueueSize = e.queueSize()
+	return e
+}
+
+// workers returns the worker count, defaulted and capped
+func (e *DefaultExecutor) workers() int {
+	if e.WorkerCount <= 0 {
+		return DefaultWorkerCount
+	}
+	return min(e.WorkerCount, MaxWorkerCount)
+}
+
+// queueSize returns the queue size, defaulting to a few jobs for each worker
+func (e *DefaultExecutor) queueSize() int {
+	if e.QueueSize <= 0 {
+		return e.workers() * jobsPerWorker
+	}
+	return e.QueueSize
+}
+
+// Execute will run the scan in the context with a default executor using the threads from the context
 func Execute(ctx ExecutionContext) {
+	NewDefaultExecutor(ctx.Threads).Execute(ctx)
+}
+
+// Execute will create a dispatcher with the worker count and queue size of the executor and then kick off every job
+// generated from the wordlists, the wordlists are read as the jobs are run rather than up front
+func (e *DefaultExecutor) Execute(ctx ExecutionContext) {
 
-	wl := &input.WordList{}
-	err := wl.NewWordList(ctx.Filepath)
+	filters, err := newFilterSet(ctx)
 	if err != nil {
+		log.Errorf("failed to create filters: %v", err)
 		return
 	}
 
-	log.Debugf("generated a wordlist with size %d\n", wl.Size())
+	bindings := ctx.Wordlists
+	if len(bindings) == 0 {
+		bindings = []input.Binding{{Filepath: ctx.Filepath, Keyword: client.Keyword}}
+	}
+	keywords := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		keywords = append(keywords, binding.Keyword)
+	}
 
-	requests, err := wl.TransformWordListToRequests(ctx.URL)
-	if err != nil {
-		return
+	if ctx.Mode != client.ModeDNS && hasKeyword(ctx, keywords) {
+		ctx.Mode = client.ModeKeyword
 	}
 
-	var jobs []*job.Job
-	for _, request := range requests {
-		jobs = append(jobs, job.NewJob(rand.Int(), request)) // #nosec G404
+	newJob := func(request client.Request) *job.Job {
+		return job.NewJob(rand.Int(), request) // #nosec G404
+	}
+	columns := output.ColumnsFor(ctx.Mode)
+	if ctx.Mode == client.ModeDNS {
+		ctx.URL = withScheme(ctx.URL)
+		scan, err := newDNSScan(ctx, filters)
+		if err != nil {
+			log.Errorf("failed to start dns scan: %v", err)
+			return
+		}
+		newJob = scan.newJob
+		columns = scan.columns()
+	} else if len(bindings) > 1 {
+		columns = append(slices.Clone(columns), output.PayloadColumn)
 	}
 
-	// Convert int to float for division, round it, convert back to int
-	dispatcher := job.NewDispatcher(int(math.Round(float64(len(requests))/3)), len(requests))
+	payloads, err := input.NewPayloadSet(bindings, ctx.PayloadMode, ctx.WordlistOptions)
+	if err != nil {
+		log.Errorf("failed to read wordlists: %v", err)
+		return
+	}
 
-	output.PrintCyanMessage(fmt.Sprintf("Running %v jobs at: %v", len(jobs), time.Now().Format("15:04:05")), true)
-	for _, jobToSubmit := range jobs {
-		dispatcher.Submit(jobToSubmit)
+	if ctx.Depth > 0 && !payloads.Repeatable() {
+		log.Warnf("cannot recurse into directories when reading a wordlist from stdin, continuing without recursion")
+		ctx.Depth = 0
 	}
 
+	source := e.newSource(payloads, ctx.Mode, newJob)
+
 	timeStarted := time.Now()
-	r := client.NewRequester(ctx.Timeout, ctx.Method, ctx.Headers, ctx.OnlyOutputFailure)
-	output.PrintMagentaMessage(fmt.Sprintf("%-3s %-20s %-10s %-15s", "", "Path", "Status", "Body Length"), true)
+	r, replay := e.newRequesters(ctx)
+	throttle := job.NewThrottle(ctx.Rate, ctx.Delay, ctx.Jitter)
+	throttle.Adaptive = !ctx.NoBackoff
+	var calibration *filter.Calibration
+	if ctx.AutoCalibrate && ctx.Mode != client.ModeDNS {
+		calibration = autoCalibrate(ctx, r, throttle, payloads.Keywords())
+		filters.Exclude(calibration)
+	}
+	// the workers and collector are started before any jobs are submitted, as the queue is only big enough to hold a
+	// few jobs for each worker and submitting blocks until there is room for more
+	dispatcher := job.NewDispatcher(e.workers(), e.queueSize())
+	dispatcher.Throttle = throttle
+	r.Retry.Throttle = throttle.Wait
 	dispatcher.Run(r)
+	c := newCollector(dispatcher, r, filters, calibration, source, columns, ctx)
+	c.replayer = replay
+	go dispatcher.Collect(c.handle)
+
+	output.PrintCyanMessage(fmt.Sprintf("Running jobs with %v workers at: %v", e.workers(),
+		timeStarted.Format("15:04:05")), true)
+	output.PrintHeader(columns)
+	dispatcher.SubmitStream(source(ctx.URL, 0))
 
 	dispatcher.Wait()
-	output.PrintCyanMessage(fmt.Sprintf("Finished %v jobs at: %v, total time taken: %v ", len(jobs),
-		time.Now().Format("15:04:05"), time.Since(timeStarted)), true)
+	replay.close()
+	timeFinished := time.Now()
+	output.PrintCyanMessage(fmt.Sprintf("Finished %v jobs at: %v, total time taken: %v ", c.jobs,
+		timeFinished.Format("15:04:05"), timeFinished.Sub(timeStarted)), true)
+	printSummary(c, payloads.Dropped())
+
+	writeReport(ctx, &output.Report{
+		Target:   ctx.URL,
+		Wordlist: wordlistNames(bindings),
+		Method:   r.Method,
+		Headers:  ctx.Headers,
+		Started:  timeStarted,
+		Finished: timeFinished,
+		Columns:  columns,
+		Results:  c.results,
+	})
+}
+
+// newSource returns a job source that streams a job for every payload in the mode, the wordlists are only read as fast
+// as the queue has room for the jobs
+func (e *DefaultExecutor) newSource(payloads *input.PayloadSet, mode client.Mode,
+	newJob func(request client.Request) *job.Job) jobSource {
+	return func(url string, depth int) <-chan *job.Job {
+		jobs := make(chan *job.Job, e.queueSize())
+		go func() {
+			defer close(jobs)
+			err := payloads.Stream(url, func(request client.Request) bool {
+				request.Mode = mode
+				j := newJob(request)
+				j.Depth = depth
+				jobs <- j
+				return true
+			})
+			if err != nil {
+				log.Errorf("failed to read wordlists: %v", err)
+			}
+		}()
+		return jobs
+	}
+}
+
+// newRequesters returns the requester used for the scan, along with a replayer for the replay proxy which is nil if
+// there is not one. Both share the protocol and TLS config from the context.
+func (e *DefaultExecutor) newRequesters(ctx ExecutionContext) (*client.Requester, *replayer) {
+	r := client.NewRequester(ctx.Timeout, ctx.Method, ctx.Headers, ctx.Body)
+	r.Retry = ctx.Retry
+	transport := client.DefaultTransportConfig()
+	transport.MaxIdleConnsPerHost = max(transport.MaxIdleConnsPerHost, e.workers())
+	if ctx.Protocol != "" {
+		transport.Protocol = ctx.Protocol
+	}
+	transport.Proxy = ctx.Proxy
+	transport.TLSClientConfig = ctx.TLSConfig
+	r.UseTransport(client.NewTransport(transport))
+	if ctx.ReplayProxy == nil {
+		return r, nil
+	}
+
+	// matched requests are replayed one at a time, so the replay proxy only needs the default connection pool
+	replayRequester := client.NewRequester(ctx.Timeout, ctx.Method, ctx.Headers, ctx.Body)
+	replayTransport := transport
+	replayTransport.MaxIdleConnsPerHost = client.DefaultTransportConfig().MaxIdleConnsPerHost
+	replayTransport.Proxy = ctx.ReplayProxy
+	replayRequester.UseTransport(client.NewTransport(replayTransport))
+	return r, newReplayer(replayRequester)
+}
+
+// autoCalibrate returns the calibration for the target in the context, a calibration that failed is still returned so
+// that any directory recursed into can be calibrated later
+func autoCalibrate(ctx ExecutionContext, r *client.Requester, throttle *job.Throttle,
+	keywords []string) *filter.Calibration {
+	calibration := newCalibration(ctx.Mode)
+	err := calibrate(r, throttle, ctx.URL, ctx.Mode, keywords, calibration)
+	if err != nil {
+		log.Warnf("auto calibration failed, continuing without it: %v", err)
+	} else {
+		output.PrintCyanMessage(fmt.Sprintf("Calibrated against wildcard responses for: %v", ctx.URL), true)
+	}
+	return calibration
+}
+
+// printSummary will print anything that went wrong with the scan that the results alone do not show, such as requests
+// failing the TLS handshake or lines dropped from the wordlists
+func printSummary(c *collector, dropped input.Dropped) {
+	if c.tlsErrors > 0 {
+		log.Warnf("%d requests failed the TLS handshake, the first because: %s", c.tlsErrors, c.tlsError)
+	}
+	if dropped.Total() > 0 {
+		output.PrintCyanMessage(fmt.Sprintf("Dropped %v entries from the wordlists: %v comments, %v blank lines and %v "+
+			"duplicates", dropped.Total(), dropped.Comments, dropped.Blank, dropped.Duplicates), true)
+	}
+}
+
+// writeReport will write the report to the output file in the context sorted by url, nothing is written without one
+func writeReport(ctx ExecutionContext, report *output.Report) {
+	if ctx.OutputFile == "" {
+		return
+	}
+	sortResults(report.Results)
+	err := output.WriteFile(ctx.OutputFile, ctx.OutputFormat, report)
+	if err != nil {
+		log.Errorf("failed to write results to %s: %v", ctx.OutputFile, err)
+		return
+	}
+	output.PrintCyanMessage(fmt.Sprintf("Wrote %v results to: %v", len(report.Results), ctx.OutputFile), true)
+}
+
+// hasKeyword returns whether any of the keywords has been placed anywhere in the request, either the url, a header
+// value or the body
+func hasKeyword(ctx ExecutionContext, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(ctx.URL, keyword) || strings.Contains(ctx.Body, keyword) {
+			return true
+		}
+		for _, value := range ctx.Headers {
+			if strings.Contains(value, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wordlistNames returns the path of each wordlist and the keyword it is bound to, for the summary of a report
+func wordlistNames(bindings []input.Binding) string {
+	if len(bindings) == 1 {
+		return bindings[0].Filepath
+	}
+	names := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		names = append(names, binding.Filepath+":"+binding.Keyword)
+	}
+	return strings.Join(names, ", ")
+}
+
+// newFilterSet will build the matchers and filters from the context, including the response length and only failure
+// options which will always drop the responses they match. In subdomain mode a word whose host could not be resolved or
+// connected to is always dropped, otherwise nearly every word in the wordlist would be shown with no status.
+func newFilterSet(ctx ExecutionContext) (*filter.Set, error) {
+	filters, err := filter.NewSet(ctx.Matchers, ctx.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.ResponseLength != 0 {
+		lengthFilter, err := filter.NewSizeFilter(strconv.Itoa(ctx.ResponseLength))
+		if err != nil {
+			return nil, err
+		}
+		filters.Exclude(lengthFilter)
+	}
+
+	if ctx.OnlyOutputFailure {
+		successFilter, err := filter.NewStatusFilter("200-299")
+		if err != nil {
+			return nil, err
+		}
+		filters.Exclude(successFilter)
+	}
+
+	if ctx.Mode == client.ModeSubdomain {
+		filters.Exclude(filter.Func(func(response *client.Response) bool {
+			return response.Error != "" && response.StatusCode == 0
+		}))
+	}
+	return filters, nil
+}
+
+// sortResults will sort the results by the url that was requested so that written output is stable between scans
+func sortResults(results []client.Response) {
+	slices.SortStableFunc(results, func(a, b client.Response) int {
+		return strings.Compare(a.URL, b.URL)
+	})
 }
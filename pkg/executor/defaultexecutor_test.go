@@ -37,7 +37,7 @@ func TestDefaultExecutor_Execute(t *testing.T) {
 		}(mockFile)
 
 		ctx := ExecutionContext{
-			Filepath:       "/Users/oscar/Projects/dizzy/pkg/testdata/testlist.txt",
+			Filepath:       mockFile,
 			URL:            mockServer.URL,
 			ResponseLength: 0,
 			Timeout:        0,
@@ -49,3 +49,38 @@ func TestDefaultExecutor_Execute(t *testing.T) {
 
 	})
 }
+
+func TestNewDefaultExecutor(t *testing.T) {
+	tests := []struct {
+		name          string
+		workers       int
+		wantWorkers   int
+		wantQueueSize int
+	}{
+		{name: "should use the default worker count", workers: 0, wantWorkers: DefaultWorkerCount,
+			wantQueueSize: DefaultWorkerCount * jobsPerWorker},
+		{name: "should use the given worker count", workers: 10, wantWorkers: 10, wantQueueSize: 20},
+		{name: "should cap the worker count", workers: 5000, wantWorkers: MaxWorkerCount,
+			wantQueueSize: MaxWorkerCount * jobsPerWorker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewDefaultExecutor(tt.workers)
+			if e.WorkerCount != tt.wantWorkers {
+				t.Errorf("WorkerCount = %d; want %d", e.WorkerCount, tt.wantWorkers)
+			}
+			if e.QueueSize != tt.wantQueueSize {
+				t.Errorf("QueueSize = %d; want %d", e.QueueSize, tt.wantQueueSize)
+			}
+		})
+	}
+
+	t.Run("should default an executor created without a constructor", func(t *testing.T) {
+		e := &DefaultExecutor{QueueSize: 5}
+		if e.workers() != DefaultWorkerCount || e.queueSize() != 5 {
+			t.Errorf("Expected %d workers and a queue of 5, but got %d and %d", DefaultWorkerCount, e.workers(),
+				e.queueSize())
+		}
+	})
+}
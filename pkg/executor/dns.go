@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/dns"
+	"github.com/ch55secake/dizzy/pkg/filter"
+	"github.com/ch55secake/dizzy/pkg/job"
+	"github.com/ch55secake/dizzy/pkg/output"
+)
+
+// dnsScan resolves each word as a subdomain of the domain, optionally following up resolved subdomains with a http
+// request in the same way as subdomain mode
+type dnsScan struct {
+	resolver *dns.Resolver
+	domain   string
+	probe    bool
+}
+
+// newDNSScan returns a scan of the domain of the given target, which can either be a url or just the domain. The domain
+// is checked for wildcard records and any result that only resolves to the wildcard is excluded by the filters.
+func newDNSScan(ctx ExecutionContext, filters *filter.Set) (*dnsScan, error) {
+	domain, err := domainOf(ctx.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := dns.NewResolver(ctx.Resolvers, ctx.Timeout)
+	wildcard := resolver.DetectWildcard(context.Background(), domain)
+	if wildcard.Detected() {
+		output.PrintMagentaMessage(fmt.Sprintf("Wildcard DNS detected for: %v, results only resolving to %v will be "+
+			"filtered", domain, strings.Join(wildcardRecords(wildcard), ",")), true)
+	}
+
+	filters.Exclude(filter.Func(func(response *client.Response) bool {
+		return response.DNS == nil || response.DNS.Empty() || wildcard.Match(response.DNS)
+	}))
+	return &dnsScan{
+		resolver: resolver,
+		domain:   domain,
+		probe:    ctx.ProbeHTTP,
+	}, nil
+}
+
+// newJob returns a job that resolves the subdomain in the request, and when probing makes the request if it resolved
+func (s *dnsScan) newJob(request client.Request) *job.Job {
+	return &job.Job{
+		ID:      rand.Int(), // #nosec G404
+		Request: request,
+		Execute: func(r *client.Requester) (client.Response, error) {
+			host := request.Subdomain + "." + s.domain
+			records, err := s.resolver.Resolve(context.Background(), host)
+			response := client.Response{
+				Subdomain: request.Subdomain,
+				Host:      host,
+				URL:       request.ToString(),
+				DNS:       &records,
+			}
+			if err != nil {
+				response.Error = err.Error()
+				return response, err
+			}
+			if !s.probe {
+				return response, nil
+			}
+
+			probed, err := r.MakeRequest(request)
+			probed.DNS = &records
+			return probed, err
+		},
+	}
+}
+
+// columns returns the dns columns, with the status and title when resolved subdomains are being probed
+func (s *dnsScan) columns() []output.Column {
+	if !s.probe {
+		return output.DNSColumns
+	}
+	return append(slices.Clone(output.DNSColumns), output.StatusColumn, output.TitleColumn)
+}
+
+// withScheme returns the target as a url, a target that is just a domain is assumed to be http
+func withScheme(target string) string {
+	if !strings.Contains(target, "://") {
+		return "http://" + target
+	}
+	return target
+}
+
+// domainOf returns the domain of the target, which can either be a url or just the domain
+func domainOf(target string) (string, error) {
+	u, err := url.Parse(withScheme(target))
+	if err != nil {
+		return "", fmt.Errorf("error parsing target %s: %w", target, err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("no domain found in target: %s", target)
+	}
+	return u.Hostname(), nil
+}
+
+// wildcardRecords returns every address and cname the wildcard resolved to, sorted so they are printed consistently
+func wildcardRecords(wildcard *dns.Wildcard) []string {
+	var records []string
+	for address := range wildcard.Addresses {
+		records = append(records, address)
+	}
+	for cname := range wildcard.CNAMEs {
+		records = append(records, cname)
+	}
+	slices.Sort(records)
+	return records
+}
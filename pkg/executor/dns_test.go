@@ -0,0 +1,34 @@
+package executor
+
+import "testing"
+
+func Test_domainOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		want      string
+		wantError bool
+	}{
+		{name: "should return the domain as is", target: "example.com", want: "example.com"},
+		{name: "should return the host of a url without the port", target: "https://example.com:8443/api", want: "example.com"},
+		{name: "should return an error when there is no domain", target: "http://", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domainOf(tt.target)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error, got domain: %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("domainOf(%q) = %q; want %q", tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"github.com/ch55secake/dizzy/pkg/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// replayQueueSize is how many matched requests can be waiting to be replayed before the collector is held back
+const replayQueueSize = 100
+
+// replayer sends every matched request again through a second proxy in the background, so that the history of a proxy
+// such as Burp only holds the interesting results rather than every request made by the scan. A nil replayer does
+// nothing so the collector does not need to check whether a replay proxy was given.
+type replayer struct {
+	requester *client.Requester
+	requests  chan client.Request
+	done      chan struct{}
+}
+
+// newReplayer returns a replayer that sends requests with the requester, which should be sending them through the
+// replay proxy. Requests are sent one at a time in the order they were matched until the replayer is closed.
+func newReplayer(requester *client.Requester) *replayer {
+	r := &replayer{
+		requester: requester,
+		requests:  make(chan client.Request, replayQueueSize),
+		done:      make(chan struct{}),
+	}
+	go func() {
+		defer close(r.done)
+		for request := range r.requests {
+			if _, err := r.requester.MakeRequest(request); err != nil {
+				log.Warnf("failed to replay %s through the replay proxy: %v", request.ToString(), err)
+			}
+		}
+	}()
+	return r
+}
+
+// replay will send the request through the replay proxy once every request matched before it has been sent
+func (r *replayer) replay(request client.Request) {
+	if r == nil {
+		return
+	}
+	r.requests <- request
+}
+
+// close blocks until every request waiting to be replayed has been sent
+func (r *replayer) close() {
+	if r == nil {
+		return
+	}
+	close(r.requests)
+	<-r.done
+}
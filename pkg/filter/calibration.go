@@ -0,0 +1,200 @@
+package filter
+
+import (
+	"hash/fnv"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// Fingerprint identifies a response by its shape rather than its content, used to recognise the generic responses that
+// a target returns for any path that does not exist
+type Fingerprint struct {
+	StatusCode int
+	BodyLength int
+	Words      int
+	Lines      int
+	Hash       uint64
+}
+
+// NewFingerprint returns the fingerprint of the given response
+func NewFingerprint(response *client.Response) Fingerprint {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(response.Body))
+	return Fingerprint{
+		StatusCode: response.StatusCode,
+		BodyLength: response.BodyLength,
+		Words:      response.Words,
+		Lines:      response.Lines,
+		Hash:       h.Sum64(),
+	}
+}
+
+// Baseline is built from every probe that returned the same status code, only the attributes that were the same across
+// all of those probes are compared, so pages that reflect the requested path can still be matched on words or lines
+type Baseline struct {
+	Fingerprint
+	SameLength bool
+	SameWords  bool
+	SameLines  bool
+	SameHash   bool
+}
+
+// Match returns whether the fingerprint looks like the baseline, the status code and every consistent attribute must be
+// the same. A baseline where nothing but the status code was consistent never matches, as it would drop every response
+// with that status code.
+func (b *Baseline) Match(fingerprint Fingerprint) bool {
+	if fingerprint.StatusCode != b.StatusCode {
+		return false
+	}
+	if !b.SameLength && !b.SameWords && !b.SameLines && !b.SameHash {
+		return false
+	}
+	return (!b.SameLength || fingerprint.BodyLength == b.BodyLength) &&
+		(!b.SameWords || fingerprint.Words == b.Words) &&
+		(!b.SameLines || fingerprint.Lines == b.Lines) &&
+		(!b.SameHash || fingerprint.Hash == b.Hash)
+}
+
+// Calibration is a filter that matches responses that look like the baselines taken for the directory, or parent
+// domain, they were requested in. It is safe to add baselines whilst the scan is running so directories can be
+// calibrated as they are discovered.
+type Calibration struct {
+	mu        sync.RWMutex
+	baselines map[string][]*Baseline
+	key       func(rawURL string) string
+}
+
+// NewCalibration returns a calibration with no baselines for scanning paths, which will not match anything until probes
+// are added
+func NewCalibration() *Calibration {
+	return &Calibration{
+		baselines: make(map[string][]*Baseline),
+		key:       directoryOf,
+	}
+}
+
+// NewSubdomainCalibration returns a calibration with no baselines for scanning subdomains, probes and responses are
+// compared against the parent domain rather than the directory, which catches wildcard dns records
+func NewSubdomainCalibration() *Calibration {
+	return &Calibration{
+		baselines: make(map[string][]*Baseline),
+		key:       parentDomainOf,
+	}
+}
+
+// NewTemplateCalibration returns a calibration with no baselines for scans where the keyword has been placed in the
+// request, every response is compared against the same baselines as the word could be anywhere in the url
+func NewTemplateCalibration() *Calibration {
+	return &Calibration{
+		baselines: make(map[string][]*Baseline),
+		key:       func(string) string { return "" },
+	}
+}
+
+// Add will build baselines from the responses to paths that should not exist, the probes are grouped by status code and
+// stored against the directory they were requested in, replacing any baselines that were there before. It returns the
+// number of baselines that were built.
+func (c *Calibration) Add(probes []client.Response) int {
+	grouped := make(map[string]map[int][]Fingerprint)
+	for i := range probes {
+		directory := c.key(probes[i].URL)
+		if grouped[directory] == nil {
+			grouped[directory] = make(map[int][]Fingerprint)
+		}
+		fingerprint := NewFingerprint(&probes[i])
+		grouped[directory][fingerprint.StatusCode] = append(grouped[directory][fingerprint.StatusCode], fingerprint)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int
+	for directory, byStatus := range grouped {
+		var baselines []*Baseline
+		for _, fingerprints := range byStatus {
+			baselines = append(baselines, newBaseline(fingerprints))
+		}
+		c.baselines[directory] = baselines
+		count += len(baselines)
+	}
+	return count
+}
+
+// Calibrated returns whether baselines have been taken for the directory of the given url
+func (c *Calibration) Calibrated(rawURL string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.baselines[c.key(rawURL)]
+	return ok
+}
+
+// Match returns whether the response looks like any of the baselines taken for the directory it was requested in
+func (c *Calibration) Match(response *client.Response) bool {
+	c.mu.RLock()
+	baselines := c.baselines[c.key(response.URL)]
+	c.mu.RUnlock()
+	if len(baselines) == 0 {
+		return false
+	}
+
+	fingerprint := NewFingerprint(response)
+	for _, baseline := range baselines {
+		if baseline.Match(fingerprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBaseline builds a baseline from fingerprints that all share the same status code
+func newBaseline(fingerprints []Fingerprint) *Baseline {
+	first := fingerprints[0]
+	baseline := &Baseline{
+		Fingerprint: first,
+		SameLength:  true,
+		SameWords:   true,
+		SameLines:   true,
+		SameHash:    true,
+	}
+	for _, fingerprint := range fingerprints[1:] {
+		baseline.SameLength = baseline.SameLength && fingerprint.BodyLength == first.BodyLength
+		baseline.SameWords = baseline.SameWords && fingerprint.Words == first.Words
+		baseline.SameLines = baseline.SameLines && fingerprint.Lines == first.Lines
+		baseline.SameHash = baseline.SameHash && fingerprint.Hash == first.Hash
+	}
+	return baseline
+}
+
+// directoryOf returns the url of the directory that the given url was requested in, without any query string, so that
+// responses can be compared against the probes that were sent to the same directory. A url with a trailing slash is
+// treated as a path within the directory rather than the directory itself.
+func directoryOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = path.Dir(strings.TrimSuffix(u.Path, "/"))
+	u.RawPath = ""
+	return u.String()
+}
+
+// parentDomainOf returns the url with the first label of the host removed and without any query string, so that
+// responses for subdomains can be compared against the probes that were sent to the same parent domain
+func parentDomainOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if _, parent, ok := strings.Cut(u.Host, "."); ok {
+		u.Host = parent
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestCalibration_Match(t *testing.T) {
+	probes := []client.Response{
+		{URL: "http://example.com/abc", StatusCode: http.StatusOK, BodyLength: 30, Words: 4, Lines: 1, Body: "page abc was not found"},
+		{URL: "http://example.com/abcdef", StatusCode: http.StatusOK, BodyLength: 33, Words: 4, Lines: 1, Body: "page abcdef was not found"},
+		{URL: "http://example.com/xyz.html", StatusCode: http.StatusNotFound, BodyLength: 9, Words: 2, Lines: 1, Body: "not found"},
+	}
+
+	calibration := NewCalibration()
+	if baselines := calibration.Add(probes); baselines != 2 {
+		t.Fatalf("Expected 2 baselines, got %d", baselines)
+	}
+
+	tests := []struct {
+		name     string
+		response client.Response
+		want     bool
+	}{
+		{
+			name:     "should match a soft 404 that reflects the path on words and lines",
+			response: client.Response{URL: "http://example.com/banana", StatusCode: http.StatusOK, BodyLength: 31, Words: 4, Lines: 1, Body: "page banana was not found"},
+			want:     true,
+		},
+		{
+			name:     "should not match a real page with the same status code",
+			response: client.Response{URL: "http://example.com/admin", StatusCode: http.StatusOK, BodyLength: 512, Words: 80, Lines: 20, Body: "admin"},
+			want:     false,
+		},
+		{
+			name:     "should match an identical 404",
+			response: client.Response{URL: "http://example.com/nope", StatusCode: http.StatusNotFound, BodyLength: 9, Words: 2, Lines: 1, Body: "not found"},
+			want:     true,
+		},
+		{
+			name:     "should not match a response in a directory that has not been calibrated",
+			response: client.Response{URL: "http://example.com/admin/banana", StatusCode: http.StatusNotFound, BodyLength: 9, Words: 2, Lines: 1, Body: "not found"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calibration.Match(&tt.response); got != tt.want {
+				t.Errorf("Match() = %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseline_Match(t *testing.T) {
+	t.Run("should not match when only the status code was consistent", func(t *testing.T) {
+		baseline := newBaseline([]Fingerprint{
+			{StatusCode: http.StatusOK, BodyLength: 1, Words: 1, Lines: 1, Hash: 1},
+			{StatusCode: http.StatusOK, BodyLength: 2, Words: 2, Lines: 2, Hash: 2},
+		})
+
+		if baseline.Match(Fingerprint{StatusCode: http.StatusOK, BodyLength: 1, Words: 1, Lines: 1, Hash: 1}) {
+			t.Errorf("Expected baseline with no consistent attributes not to match")
+		}
+	})
+}
+
+func Test_directoryOf(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "http://example.com/admin", want: "http://example.com/"},
+		{url: "http://example.com/admin/", want: "http://example.com/"},
+		{url: "http://example.com/api/users?id=1", want: "http://example.com/api"},
+		{url: "http://example.com:8080/api/users/", want: "http://example.com:8080/api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := directoryOf(tt.url); got != tt.want {
+				t.Errorf("directoryOf(%q) = %q; want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubdomainCalibration_Match(t *testing.T) {
+	t.Run("should match a wildcard response for any subdomain of the same parent domain", func(t *testing.T) {
+		calibration := NewSubdomainCalibration()
+		calibration.Add([]client.Response{
+			{URL: "http://abc.example.com:8080/", StatusCode: http.StatusOK, BodyLength: 5, Body: "hello"},
+			{URL: "http://xyz.example.com:8080/", StatusCode: http.StatusOK, BodyLength: 5, Body: "hello"},
+		})
+
+		wildcard := client.Response{URL: "http://www.example.com:8080/", StatusCode: http.StatusOK, BodyLength: 5, Body: "hello"}
+		if !calibration.Match(&wildcard) {
+			t.Errorf("Expected wildcard response to match")
+		}
+
+		other := client.Response{URL: "http://www.example.org:8080/", StatusCode: http.StatusOK, BodyLength: 5, Body: "hello"}
+		if calibration.Match(&other) {
+			t.Errorf("Expected response for a different parent domain not to match")
+		}
+	})
+}
@@ -0,0 +1,233 @@
+// Package filter provides matchers and filters that decide which responses are shown to the user
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// Filter decides whether a response matches some criteria, the same implementation is used both to match responses
+// that should be kept and to filter out responses that should be dropped
+type Filter interface {
+	Match(response *client.Response) bool
+}
+
+// Func is an adapter to allow a plain function to be used as a filter
+type Func func(response *client.Response) bool
+
+// Match calls the function with the response
+func (f Func) Match(response *client.Response) bool {
+	return f(response)
+}
+
+// Mode is how the filters in a chain are combined
+type Mode string
+
+const (
+	// ModeAnd requires every filter in the chain to match
+	ModeAnd Mode = "and"
+	// ModeOr requires at least one filter in the chain to match
+	ModeOr Mode = "or"
+)
+
+// ParseMode will return the mode matching the given name, an empty name will default to or
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(name)); mode {
+	case ModeAnd, ModeOr:
+		return mode, nil
+	case "":
+		return ModeOr, nil
+	default:
+		return "", fmt.Errorf("unsupported mode: %s, must be one of and or or", name)
+	}
+}
+
+// Chain combines multiple filters together with the given mode
+type Chain struct {
+	Filters []Filter
+	Mode    Mode
+}
+
+// Empty returns whether the chain has any filters in it
+func (c *Chain) Empty() bool {
+	return len(c.Filters) == 0
+}
+
+// Match will return whether the response matches the chain, an empty chain never matches
+func (c *Chain) Match(response *client.Response) bool {
+	if c.Empty() {
+		return false
+	}
+	for _, filter := range c.Filters {
+		matched := filter.Match(response)
+		if c.Mode == ModeAnd && !matched {
+			return false
+		}
+		if c.Mode != ModeAnd && matched {
+			return true
+		}
+	}
+	return c.Mode == ModeAnd
+}
+
+// Range is an inclusive range of numbers, a single number is a range where min and max are the same
+type Range struct {
+	Min int
+	Max int
+}
+
+// Contains returns whether the value is within the range
+func (r Range) Contains(value int) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+// ParseRanges will parse a comma separated list of numbers and ranges, i.e. 200,301-302,500-599
+func ParseRanges(spec string) ([]Range, error) {
+	var ranges []Range
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(part, "-")
+		minimum, err := strconv.Atoi(strings.TrimSpace(low))
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q in %q: %w", low, spec, err)
+		}
+		maximum := minimum
+		if isRange {
+			maximum, err = strconv.Atoi(strings.TrimSpace(high))
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q in %q: %w", high, spec, err)
+			}
+		}
+		if minimum > maximum {
+			return nil, fmt.Errorf("invalid range %q, start is greater than end", part)
+		}
+
+		ranges = append(ranges, Range{Min: minimum, Max: maximum})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no numbers found in %q", spec)
+	}
+	return ranges, nil
+}
+
+// NumberFilter matches when a number taken from the response falls within any of the ranges
+type NumberFilter struct {
+	Name   string
+	Ranges []Range
+	value  func(response *client.Response) int
+}
+
+// Match returns whether the number taken from the response is within any of the ranges
+func (f *NumberFilter) Match(response *client.Response) bool {
+	value := f.value(response)
+	for _, r := range f.Ranges {
+		if r.Contains(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// newNumberFilter will parse the spec into ranges and return a filter that checks the given value against them
+func newNumberFilter(name, spec string, value func(response *client.Response) int) (*NumberFilter, error) {
+	ranges, err := ParseRanges(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s filter: %w", name, err)
+	}
+	return &NumberFilter{
+		Name:   name,
+		Ranges: ranges,
+		value:  value,
+	}, nil
+}
+
+// NewStatusFilter returns a filter on the status code of the response, the spec can be a list of codes and ranges or
+// all to match every status code
+func NewStatusFilter(spec string) (Filter, error) {
+	if strings.EqualFold(strings.TrimSpace(spec), "all") {
+		spec = "0-999"
+	}
+	return newNumberFilter("status", spec, func(response *client.Response) int {
+		return response.StatusCode
+	})
+}
+
+// NewSizeFilter returns a filter on the length of the response body
+func NewSizeFilter(spec string) (Filter, error) {
+	return newNumberFilter("size", spec, func(response *client.Response) int {
+		return response.BodyLength
+	})
+}
+
+// NewWordFilter returns a filter on the number of words in the response body
+func NewWordFilter(spec string) (Filter, error) {
+	return newNumberFilter("words", spec, func(response *client.Response) int {
+		return response.Words
+	})
+}
+
+// NewLineFilter returns a filter on the number of lines in the response body
+func NewLineFilter(spec string) (Filter, error) {
+	return newNumberFilter("lines", spec, func(response *client.Response) int {
+		return response.Lines
+	})
+}
+
+// RegexFilter matches when the response body matches the regular expression
+type RegexFilter struct {
+	Regex *regexp.Regexp
+}
+
+// NewRegexFilter compiles the pattern and returns a filter on the response body
+func NewRegexFilter(pattern string) (Filter, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing regex filter: %w", err)
+	}
+	return &RegexFilter{Regex: re}, nil
+}
+
+// Match returns whether the response body matches the regular expression
+func (f *RegexFilter) Match(response *client.Response) bool {
+	return f.Regex.MatchString(response.Body)
+}
+
+// TimeFilter matches when the time taken to respond is either above or below the threshold
+type TimeFilter struct {
+	Threshold time.Duration
+	Above     bool
+}
+
+// NewTimeFilter returns a filter on how long the response took, the spec is a threshold in milliseconds prefixed with
+// either > or <, i.e. >100 matches responses that took longer than 100ms
+func NewTimeFilter(spec string) (Filter, error) {
+	spec = strings.TrimSpace(spec)
+	if len(spec) < 2 || (spec[0] != '>' && spec[0] != '<') {
+		return nil, fmt.Errorf("error parsing time filter: %q must be a number of milliseconds prefixed with > or <", spec)
+	}
+	milliseconds, err := strconv.Atoi(strings.TrimSpace(spec[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing time filter: %w", err)
+	}
+	return &TimeFilter{
+		Threshold: time.Duration(milliseconds) * time.Millisecond,
+		Above:     spec[0] == '>',
+	}, nil
+}
+
+// Match returns whether the response took longer, or shorter, than the threshold
+func (f *TimeFilter) Match(response *client.Response) bool {
+	if f.Above {
+		return response.Duration > f.Threshold
+	}
+	return response.Duration < f.Threshold
+}
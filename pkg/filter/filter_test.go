@@ -0,0 +1,143 @@
+package filter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestParseRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		want      []Range
+		wantError bool
+	}{
+		{
+			name: "should parse a single number",
+			spec: "200",
+			want: []Range{{Min: 200, Max: 200}},
+		},
+		{
+			name: "should parse a list of numbers and ranges",
+			spec: "200, 301-302,500-599",
+			want: []Range{{Min: 200, Max: 200}, {Min: 301, Max: 302}, {Min: 500, Max: 599}},
+		},
+		{
+			name:      "should return an error when the range is backwards",
+			spec:      "302-301",
+			wantError: true,
+		},
+		{
+			name:      "should return an error when the spec is not a number",
+			spec:      "banana",
+			wantError: true,
+		},
+		{
+			name:      "should return an error when the spec is empty",
+			spec:      ",",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRanges(tt.spec)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error, got ranges: %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d ranges, got %d", len(tt.want), len(got))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("range %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilters_Match(t *testing.T) {
+	response := &client.Response{
+		StatusCode: http.StatusForbidden,
+		BodyLength: 120,
+		Words:      20,
+		Lines:      4,
+		Body:       "<html><title>Access Denied</title></html>",
+		Duration:   150 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name      string
+		newFilter func(spec string) (Filter, error)
+		spec      string
+		want      bool
+	}{
+		{name: "status should match a code within a range", newFilter: NewStatusFilter, spec: "400-403", want: true},
+		{name: "status should not match a code outside the list", newFilter: NewStatusFilter, spec: "200,404", want: false},
+		{name: "status should match all", newFilter: NewStatusFilter, spec: "all", want: true},
+		{name: "size should match the body length", newFilter: NewSizeFilter, spec: "120", want: true},
+		{name: "size should not match a different length", newFilter: NewSizeFilter, spec: "0", want: false},
+		{name: "words should match the word count", newFilter: NewWordFilter, spec: "10-20", want: true},
+		{name: "lines should match the line count", newFilter: NewLineFilter, spec: "4", want: true},
+		{name: "regex should match the body", newFilter: NewRegexFilter, spec: "(?i)access denied", want: true},
+		{name: "regex should not match the body", newFilter: NewRegexFilter, spec: "welcome", want: false},
+		{name: "time should match when slower than threshold", newFilter: NewTimeFilter, spec: ">100", want: true},
+		{name: "time should not match when slower than threshold", newFilter: NewTimeFilter, spec: "<100", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.newFilter(tt.spec)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got := f.Match(response); got != tt.want {
+				t.Errorf("Match() = %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTimeFilter_Error(t *testing.T) {
+	t.Run("should return an error when the threshold has no comparison", func(t *testing.T) {
+		_, err := NewTimeFilter("100")
+		if err == nil {
+			t.Errorf("Expected an error, but got nil")
+		}
+	})
+}
+
+func TestChain_Match(t *testing.T) {
+	response := &client.Response{StatusCode: http.StatusOK, BodyLength: 10}
+	status, _ := NewStatusFilter("200")
+	size, _ := NewSizeFilter("20")
+
+	tests := []struct {
+		name  string
+		chain Chain
+		want  bool
+	}{
+		{name: "empty chain should never match", chain: Chain{Mode: ModeOr}, want: false},
+		{name: "or should match when any filter matches", chain: Chain{Filters: []Filter{status, size}, Mode: ModeOr}, want: true},
+		{name: "and should not match when one filter does not match", chain: Chain{Filters: []Filter{status, size}, Mode: ModeAnd}, want: false},
+		{name: "and should match when every filter matches", chain: Chain{Filters: []Filter{status}, Mode: ModeAnd}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.chain.Match(response); got != tt.want {
+				t.Errorf("Match() = %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// Criteria are the raw specs provided by the user for either the matchers or the filters, an empty spec is ignored
+type Criteria struct {
+	Status string
+	Size   string
+	Words  string
+	Lines  string
+	Regex  string
+	Time   string
+	Mode   string
+}
+
+// Set is the matchers and filters that decide whether a response should be kept, a response is kept when it matches
+// the matchers, or there are no matchers, and does not match the filters or any of the excludes. Excludes are not
+// combined with the filter mode and will always drop a response they match.
+type Set struct {
+	Matchers Chain
+	Filters  Chain
+	Excludes []Filter
+}
+
+// NewSet will build the set from the given matcher and filter criteria
+func NewSet(matchers, filters Criteria) (*Set, error) {
+	matcherChain, err := NewChain(matchers)
+	if err != nil {
+		return nil, err
+	}
+	filterChain, err := NewChain(filters)
+	if err != nil {
+		return nil, err
+	}
+	return &Set{
+		Matchers: *matcherChain,
+		Filters:  *filterChain,
+	}, nil
+}
+
+// NewChain will build a chain from every spec provided in the criteria
+func NewChain(criteria Criteria) (*Chain, error) {
+	mode, err := ParseMode(criteria.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := &Chain{Mode: mode}
+	specs := []struct {
+		spec      string
+		newFilter func(spec string) (Filter, error)
+	}{
+		{criteria.Status, NewStatusFilter},
+		{criteria.Size, NewSizeFilter},
+		{criteria.Words, NewWordFilter},
+		{criteria.Lines, NewLineFilter},
+		{criteria.Regex, NewRegexFilter},
+		{criteria.Time, NewTimeFilter},
+	}
+	for _, s := range specs {
+		if s.spec == "" {
+			continue
+		}
+		filter, err := s.newFilter(s.spec)
+		if err != nil {
+			return nil, err
+		}
+		chain.Filters = append(chain.Filters, filter)
+	}
+	return chain, nil
+}
+
+// Exclude will add a filter to the set that drops any response it matches regardless of the filter mode
+func (s *Set) Exclude(filter Filter) {
+	s.Excludes = append(s.Excludes, filter)
+}
+
+// Keep returns whether the response should be shown to the user
+func (s *Set) Keep(response *client.Response) bool {
+	if !s.Matchers.Empty() && !s.Matchers.Match(response) {
+		return false
+	}
+	for _, exclude := range s.Excludes {
+		if exclude.Match(response) {
+			return false
+		}
+	}
+	return !s.Filters.Match(response)
+}
@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestSet_Keep(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers Criteria
+		filters  Criteria
+		response client.Response
+		want     bool
+	}{
+		{
+			name:     "should keep everything when there are no matchers or filters",
+			response: client.Response{StatusCode: http.StatusNotFound},
+			want:     true,
+		},
+		{
+			name:     "should drop a response that does not match the matchers",
+			matchers: Criteria{Status: "200-299"},
+			response: client.Response{StatusCode: http.StatusNotFound},
+			want:     false,
+		},
+		{
+			name:     "should drop a response that matches the filters",
+			matchers: Criteria{Status: "all"},
+			filters:  Criteria{Size: "0"},
+			response: client.Response{StatusCode: http.StatusOK, BodyLength: 0},
+			want:     false,
+		},
+		{
+			name:     "should keep a response that only matches one of the and filters",
+			filters:  Criteria{Status: "200", Size: "0", Mode: "and"},
+			response: client.Response{StatusCode: http.StatusOK, BodyLength: 10},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := NewSet(tt.matchers, tt.filters)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got := set.Keep(&tt.response); got != tt.want {
+				t.Errorf("Keep() = %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_Exclude(t *testing.T) {
+	t.Run("should drop a response matching an exclude regardless of the filter mode", func(t *testing.T) {
+		set, err := NewSet(Criteria{}, Criteria{Status: "500", Size: "1", Mode: "and"})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		success, _ := NewStatusFilter("200-299")
+		set.Exclude(success)
+
+		if set.Keep(&client.Response{StatusCode: http.StatusOK}) {
+			t.Errorf("Expected response to be excluded")
+		}
+		if !set.Keep(&client.Response{StatusCode: http.StatusNotFound}) {
+			t.Errorf("Expected response to be kept")
+		}
+	})
+}
+
+func TestNewSet_Error(t *testing.T) {
+	t.Run("should return an error when a spec cannot be parsed", func(t *testing.T) {
+		_, err := NewSet(Criteria{Regex: "("}, Criteria{})
+		if err == nil {
+			t.Errorf("Expected an error, but got nil")
+		}
+	})
+
+	t.Run("should return an error when the mode is not supported", func(t *testing.T) {
+		_, err := NewSet(Criteria{}, Criteria{Mode: "xor"})
+		if err == nil {
+			t.Errorf("Expected an error, but got nil")
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic bytes at the start of a file that identify how it has been compressed
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompressor reads the decompressed content of a file, closing it closes both the decompressor and the file
+type decompressor struct {
+	io.Reader
+	close func() error
+	file  io.Closer
+}
+
+// Close will close the decompressor and then the file underneath it
+func (d *decompressor) Close() error {
+	var err error
+	if d.close != nil {
+		err = d.close()
+	}
+	return errors.Join(err, d.file.Close())
+}
+
+// isBzip2 returns whether the magic bytes are the start of a bzip2 file, which is followed by the block size from 1 to 9
+// so that a plain wordlist that happens to start with BZh, such as BZhome, is not mistaken for one
+func isBzip2(magic []byte) bool {
+	return len(magic) > len(bzip2Magic) && bytes.HasPrefix(magic, bzip2Magic) &&
+		magic[len(bzip2Magic)] >= '1' && magic[len(bzip2Magic)] <= '9'
+}
+
+// decompress will detect whether the file has been compressed with gzip, bzip2 or zstd from its first few bytes and
+// return a reader that decompresses it as it is read, anything else is read as is
+func decompress(file io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(file)
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzip file: %w", err)
+		}
+		return &decompressor{Reader: gz, close: gz.Close, file: file}, nil
+	case isBzip2(magic):
+		return &decompressor{Reader: bzip2.NewReader(buffered), file: file}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("error reading zstd file: %w", err)
+		}
+		return &decompressor{Reader: zr, close: func() error { zr.Close(); return nil }, file: file}, nil
+	default:
+		return &decompressor{Reader: buffered, file: file}, nil
+	}
+}
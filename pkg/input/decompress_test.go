@@ -0,0 +1,82 @@
+package input
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// bzip2Words is "admin\nlogin\n" compressed with bzip2, which the standard library can only decompress
+var bzip2Words = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x7f, 0x37, 0x1a, 0x27, 0x00, 0x00, 0x01, 0x41, 0x00,
+	0x00, 0x10, 0x24, 0xa7, 0xa0, 0x00, 0x21, 0xa0, 0x1b, 0x50, 0x83, 0x26, 0x21, 0xa9, 0xa3, 0x4c, 0xe6, 0x8f, 0x17,
+	0x72, 0x45, 0x38, 0x50, 0x90, 0x7f, 0x37, 0x1a, 0x27,
+}
+
+func Test_decompress(t *testing.T) {
+	words := []byte("admin\nlogin\n")
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	if _, err := gw.Write(words); err != nil {
+		t.Fatalf("failed to gzip words: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to gzip words: %v", err)
+	}
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	zst := zw.EncodeAll(words, nil)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zstd writer: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []string
+	}{
+		{name: "should read a plain file as is", content: words, want: []string{"admin", "login"}},
+		{name: "should decompress a gzip file", content: gz.Bytes(), want: []string{"admin", "login"}},
+		{name: "should decompress a bzip2 file", content: bzip2Words, want: []string{"admin", "login"}},
+		{name: "should decompress a zstd file", content: zst, want: []string{"admin", "login"}},
+		{name: "should read a file shorter than the magic bytes", content: []byte("a"), want: []string{"a"}},
+		{name: "should read a file starting with the bzip2 magic but no block size as is",
+			content: []byte("BZhome\nlogin\n"), want: []string{"BZhome", "login"}},
+		{name: "should read a file that is only the bzip2 magic as is", content: []byte("BZh"), want: []string{"BZh"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFile := filepath.Join(t.TempDir(), "words")
+			if err := os.WriteFile(mockFile, tt.content, 0600); err != nil {
+				t.Fatalf("failed to create mock file: %v", err)
+			}
+
+			reader, err := Options{}.Open(mockFile)
+			if err != nil {
+				t.Fatalf("Open returned an unexpected error: %v", err)
+			}
+			defer reader.Close()
+
+			var got []string
+			for word, ok := reader.Next(); ok; word, ok = reader.Next() {
+				got = append(got, word)
+			}
+			if err := reader.Err(); err != nil {
+				t.Errorf("Err returned an unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("words = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,17 +5,58 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/ch55secake/dizzy/pkg/client"
 )
 
-// WordList contains the current list of words in a slice of bytes and filepath
+// extRegex matches the placeholder that is replaced with each extension
+var extRegex = regexp.MustCompile(`(?i)%ext%`)
+
+// WordList contains the current list of words in a slice of bytes and filepath, the options control how each word is
+// expanded as the file is read
 type WordList struct {
+	Options
 	data     [][]byte
 	filepath string
+	dropped  Dropped
+}
+
+// Options control how each line of a wordlist is cleaned up and how each word is expanded when it is read
+type Options struct {
+	// SkipComments drops lines starting with #, as found at the top of many published wordlists
+	SkipComments bool
+	// SkipBlank drops lines that are empty or only contain whitespace, which would otherwise request the bare url
+	SkipBlank bool
+	// Trim removes leading and trailing whitespace from each line
+	Trim bool
+	// StripLeadingSlashes removes any slashes at the start of each line, as the word is appended after a slash
+	StripLeadingSlashes bool
+	// Dedupe drops any word that has already been read from the wordlist
+	Dedupe bool
+	// Extensions replace the %ext% placeholder in a word, creating a word for each extension
+	Extensions []string
+	// AppendExtensions will also add a word for each extension appended to every word without the placeholder
+	AppendExtensions bool
+	// Mutations are applied to every word before the extensions, a word can be mutated into many words
+	Mutations Chain
+}
+
+// fansOut returns whether a single line can become more than one word, through mutations or extensions
+func (o Options) fansOut() bool {
+	return len(o.Mutations) > 0 || len(o.Extensions) > 0
+}
+
+// literal returns the options without any mutations or extensions, so that each line is read as a single word
+func (o Options) literal() Options {
+	o.Mutations = nil
+	o.Extensions = nil
+	o.AppendExtensions = false
+	return o
 }
 
 // TransformWordListToRequests transform all list of words to a list of requests that will be handled by the executor
@@ -50,6 +91,11 @@ func (w *WordList) FilePath() string {
 	return w.filepath
 }
 
+// Dropped returns how many lines were dropped whilst the wordlist was read
+func (w *WordList) Dropped() Dropped {
+	return w.dropped
+}
+
 // Size return the current length of the wordlist in memory
 func (w *WordList) Size() int {
 	return len(w.data)
@@ -59,8 +105,7 @@ func (w *WordList) Size() int {
 func isFileReadable(filepath string) (bool, error) {
 	_, err := os.Stat(filepath)
 	if err != nil { // this will get statistics about the provided file
-		log.Fatalf("file stat returned error: %v", err)
-		return false, err
+		return false, fmt.Errorf("file stat returned error: %w", err)
 	}
 	f, err := os.Open(filepath) // #nosec G304
 	if err != nil {
@@ -68,33 +113,36 @@ func isFileReadable(filepath string) (bool, error) {
 	}
 	err = f.Close()
 	if err != nil {
-		log.Fatalf("Error closing file: %v", err)
-		return false, err
+		return false, fmt.Errorf("error closing file: %w", err)
 	}
 	return true, nil
 }
 
-// openFile will open and return *os.File to the caller of the method, if opening the file fails will return error
-// back up to the caller
-func openFile(filepath string) (*os.File, error) {
+// openFile will open the file and return it to the caller of the method, a file compressed with gzip, bzip2 or zstd is
+// decompressed as it is read. If opening the file fails will return error back up to the caller
+func openFile(filepath string) (io.ReadCloser, error) {
 	var file *os.File
 	var err error
-	if filepath == "-" {
+	if filepath == Stdin {
 		file = os.Stdin
 	} else {
 		file, err = os.Open(filepath) // #nosec G304
 		if err != nil {
-			log.Fatalf("Error opening file: %v", err)
-			return nil, err
+			return nil, fmt.Errorf("error opening file: %w", err)
 		}
 	}
 
-	return file, nil
+	reader, err := decompress(file)
+	if err != nil {
+		closeFile(file)
+		return nil, err
+	}
+	return reader, nil
 }
 
 // closeFile will close the given file
-func closeFile(file *os.File) {
-	defer func(file *os.File) {
+func closeFile(file io.Closer) {
+	defer func(file io.Closer) {
 		err := file.Close()
 		if err != nil {
 			log.Fatalf("Error closing file: %v", err)
@@ -102,25 +150,61 @@ func closeFile(file *os.File) {
 	}(file)
 }
 
-// readFile will read in the file but first check whether the file is a text file
+// readFile will read every word in the file into memory, large wordlists should be read with a Reader instead
 func (w *WordList) readFile(filepath string) error {
-	file, _ := openFile(filepath)
+	file, err := openFile(filepath)
+	if err != nil {
+		return err
+	}
+	reader := &Reader{Options: w.Options, file: file, scanner: bufio.NewScanner(file)}
+	defer reader.Close()
 
 	var data [][]byte
-	reader := bufio.NewScanner(file)
-	re := regexp.MustCompile(`(?i)%ext%`)
-	for reader.Scan() {
-		if re.MatchString(reader.Text()) {
-			data = append(data, []byte(reader.Text()))
-		} else {
-			text := reader.Text()
-			data = append(data, []byte(text))
-		}
+	for word, ok := reader.Next(); ok; word, ok = reader.Next() {
+		data = append(data, []byte(word))
 	}
 
-	closeFile(file)
-
 	w.filepath = filepath
 	w.data = data
+	w.dropped = reader.Dropped()
 	return reader.Err()
 }
+
+// expand will return the words for the given line, a word with the %ext% placeholder becomes a word for each extension
+// and a plain word is kept as is, followed by a word for each extension when they are being appended. Without any
+// extensions the word is always kept as is.
+func (o Options) expand(word string) []string {
+	if len(o.Extensions) == 0 {
+		return []string{word}
+	}
+	if extRegex.MatchString(word) {
+		words := make([]string, 0, len(o.Extensions))
+		for _, ext := range o.Extensions {
+			words = append(words, extRegex.ReplaceAllLiteralString(word, ext))
+		}
+		return words
+	}
+	if !o.AppendExtensions {
+		return []string{word}
+	}
+	words := make([]string, 0, len(o.Extensions)+1)
+	words = append(words, word)
+	for _, ext := range o.Extensions {
+		words = append(words, word+"."+ext)
+	}
+	return words
+}
+
+// ParseExtensions will split a comma separated list of extensions, dropping any leading dot and empty extensions
+func ParseExtensions(extensions []string) []string {
+	var parsed []string
+	for _, ext := range extensions {
+		for _, e := range strings.Split(ext, ",") {
+			e = strings.TrimPrefix(strings.TrimSpace(e), ".")
+			if e != "" {
+				parsed = append(parsed, e)
+			}
+		}
+	}
+	return parsed
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"testing"
 )
 
@@ -255,3 +256,54 @@ func TestWordList_TransformWordListToRequests(t *testing.T) {
 		}
 	})
 }
+
+func TestOptions_expand(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		word    string
+		want    []string
+	}{
+		{
+			name: "should keep the word when there are no extensions",
+			word: "admin%EXT%",
+			want: []string{"admin%EXT%"},
+		},
+		{
+			name:    "should replace the placeholder with each extension",
+			options: Options{Extensions: []string{"php", "bak"}},
+			word:    "index.%ext%",
+			want:    []string{"index.php", "index.bak"},
+		},
+		{
+			name:    "should keep a plain word as is without appending",
+			options: Options{Extensions: []string{"php", "bak"}},
+			word:    "admin",
+			want:    []string{"admin"},
+		},
+		{
+			name:    "should append each extension to a plain word",
+			options: Options{Extensions: []string{"php", "bak"}, AppendExtensions: true},
+			word:    "admin",
+			want:    []string{"admin", "admin.php", "admin.bak"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.expand(tt.word); !slices.Equal(got, tt.want) {
+				t.Errorf("expand() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	t.Run("should drop leading dots and empty extensions", func(t *testing.T) {
+		got := ParseExtensions([]string{".php", "bak,,json", " "})
+		want := []string{"php", "bak", "json"}
+		if !slices.Equal(got, want) {
+			t.Errorf("ParseExtensions() = %v; want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package input
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// PayloadMode is how the words from multiple wordlists are combined into the payload for each request
+type PayloadMode string
+
+const (
+	// PayloadClusterbomb tries every combination of words from each wordlist, this is the default
+	PayloadClusterbomb PayloadMode = "clusterbomb"
+	// PayloadPitchfork uses the words on the same line of each wordlist together, stopping at the shortest wordlist
+	PayloadPitchfork PayloadMode = "pitchfork"
+)
+
+// keywordRegex is what a keyword bound to a wordlist can look like, i.e. FUZZ, USER or DIR_2
+var keywordRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// ParsePayloadMode will return the payload mode matching the given name, an empty name will default to clusterbomb
+func ParsePayloadMode(name string) (PayloadMode, error) {
+	switch mode := PayloadMode(strings.ToLower(name)); mode {
+	case PayloadClusterbomb, PayloadPitchfork:
+		return mode, nil
+	case "":
+		return PayloadClusterbomb, nil
+	default:
+		return "", fmt.Errorf("unsupported payload mode: %s", name)
+	}
+}
+
+// Binding is a wordlist and the keyword that its words replace in the request
+type Binding struct {
+	Filepath string
+	Keyword  string
+}
+
+// ParseBinding will parse a wordlist given as path:KEYWORD, a wordlist without a keyword is bound to the default keyword
+func ParseBinding(s string) Binding {
+	i := strings.LastIndex(s, ":")
+	if i == -1 || !keywordRegex.MatchString(s[i+1:]) {
+		return Binding{Filepath: s, Keyword: client.Keyword}
+	}
+	return Binding{Filepath: s[:i], Keyword: s[i+1:]}
+}
+
+// PayloadSet streams the payloads for each request from a wordlist bound to each keyword. The first wordlist is always
+// streamed, in clusterbomb mode every other wordlist is read into memory as it is repeated for each word of the first,
+// whereas in pitchfork mode every wordlist is streamed line by line. Only the words of the first wordlist are mutated
+// and expanded with extensions, so that a list of passwords does not also get a .php variant of every password.
+type PayloadSet struct {
+	bindings     []Binding
+	mode         PayloadMode
+	options      Options
+	inner        [][]string
+	mu           sync.Mutex
+	dropped      *Dropped
+	innerDropped Dropped
+}
+
+// NewPayloadSet checks the wordlist for each binding can be read with the given options, every keyword must only be
+// bound to a single wordlist
+func NewPayloadSet(bindings []Binding, mode PayloadMode, options Options) (*PayloadSet, error) {
+	if len(bindings) == 0 {
+		return nil, fmt.Errorf("no wordlists were provided")
+	}
+
+	if mode == PayloadPitchfork && len(bindings) > 1 && options.fansOut() {
+		return nil, fmt.Errorf("mutations and extensions cannot be used in pitchfork mode, as they would turn a line of " +
+			"one wordlist into many words and the wordlists would no longer line up")
+	}
+
+	p := &PayloadSet{bindings: bindings, mode: mode, options: options}
+	seen := make(map[string]struct{}, len(bindings))
+	for i, binding := range bindings {
+		if _, ok := seen[binding.Keyword]; ok {
+			return nil, fmt.Errorf("keyword %s is bound to more than one wordlist", binding.Keyword)
+		}
+		seen[binding.Keyword] = struct{}{}
+		if binding.Filepath == Stdin && i > 0 && mode == PayloadClusterbomb {
+			return nil, fmt.Errorf("only the first wordlist can be read from stdin in clusterbomb mode")
+		}
+		if binding.Filepath == Stdin {
+			continue
+		}
+		if _, err := isFileReadable(binding.Filepath); err != nil {
+			return nil, fmt.Errorf("failed to check if file is readable: %w", err)
+		}
+	}
+
+	if mode == PayloadClusterbomb {
+		for _, binding := range bindings[1:] {
+			wl := &WordList{Options: options.literal()}
+			err := wl.NewWordList(binding.Filepath)
+			if err != nil {
+				return nil, err
+			}
+			p.innerDropped = p.innerDropped.Add(wl.Dropped())
+			words := make([]string, 0, wl.Size())
+			for _, word := range wl.data {
+				words = append(words, string(word))
+			}
+			p.inner = append(p.inner, words)
+		}
+	}
+	return p, nil
+}
+
+// Keywords returns the keyword bound to each wordlist in the order they were given
+func (p *PayloadSet) Keywords() []string {
+	keywords := make([]string, 0, len(p.bindings))
+	for _, binding := range p.bindings {
+		keywords = append(keywords, binding.Keyword)
+	}
+	return keywords
+}
+
+// Dropped returns how many lines and words were dropped from the wordlists, which is only known once they have been
+// streamed in full for the first time
+func (p *PayloadSet) Dropped() Dropped {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.dropped == nil {
+		return p.innerDropped
+	}
+	return p.dropped.Add(p.innerDropped)
+}
+
+// Repeatable returns whether the payloads can be streamed more than once, which is not the case when reading stdin
+func (p *PayloadSet) Repeatable() bool {
+	for _, binding := range p.bindings {
+		if binding.Filepath == Stdin {
+			return false
+		}
+	}
+	return true
+}
+
+// Stream will call fn with a request for each payload as the wordlists are read, stopping early if fn returns false.
+// The word from the first wordlist is used as the subdomain of the request, a single wordlist bound to the default
+// keyword does not need a payload. fn is expected to block when the caller cannot keep up, which stops the wordlists
+// being read any faster than the requests are made.
+func (p *PayloadSet) Stream(url string, fn func(request client.Request) bool) error {
+	keywords := p.Keywords()
+	return p.each(func(words []string) bool {
+		request := client.Request{
+			URL:       url,
+			Subdomain: words[0],
+		}
+		if len(keywords) > 1 || keywords[0] != client.Keyword {
+			request.Payload = make(map[string]string, len(words))
+			for i, word := range words {
+				request.Payload[keywords[i]] = word
+			}
+		}
+		return fn(request)
+	})
+}
+
+// each will call fn with the words for every payload, in clusterbomb mode the last wordlist changes the fastest
+func (p *PayloadSet) each(fn func(words []string) bool) error {
+	readers := make([]*Reader, 0, len(p.bindings))
+	defer func() {
+		for _, reader := range readers {
+			reader.Close()
+		}
+	}()
+	// rows dropped in pitchfork mode are counted once for the whole row rather than against any single wordlist
+	var rowsDropped Dropped
+	finished := func(err error) error {
+		if err != nil {
+			return err
+		}
+		dropped := rowsDropped
+		for _, reader := range readers {
+			dropped = dropped.Add(reader.Dropped())
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.dropped == nil {
+			p.dropped = &dropped
+		}
+		return nil
+	}
+	streamed := p.bindings
+	if p.mode == PayloadClusterbomb {
+		streamed = p.bindings[:1]
+	}
+	for _, binding := range streamed {
+		reader, err := p.options.Open(binding.Filepath)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, reader)
+	}
+
+	if p.mode == PayloadPitchfork {
+		for {
+			words, ok, err := p.row(readers, &rowsDropped)
+			if !ok {
+				return finished(err)
+			}
+			if words != nil && !fn(words) {
+				return nil
+			}
+		}
+	}
+
+	for word, ok := readers[0].Next(); ok; word, ok = readers[0].Next() {
+		if !p.combine([]string{word}, fn) {
+			return nil
+		}
+	}
+	return finished(readers[0].Err())
+}
+
+// row reads the same line from every wordlist in pitchfork mode, so that the words used together always come from the
+// same line. A line dropped from any wordlist drops the whole row and returns nil, otherwise the wordlists would go out
+// of step. Once any wordlist has run out it returns false along with any error reading it.
+func (p *PayloadSet) row(readers []*Reader, dropped *Dropped) ([]string, bool, error) {
+	words := make([]string, len(readers))
+	for i, reader := range readers {
+		line, ok := reader.line()
+		if !ok {
+			return nil, false, reader.Err()
+		}
+		words[i] = line
+	}
+	for i := range words {
+		word, ok := p.options.normalise(words[i], dropped)
+		if !ok {
+			return nil, true, nil
+		}
+		words[i] = word
+	}
+	if p.options.Dedupe && readers[0].duplicate(strings.Join(words, "\x00")) {
+		dropped.Duplicates++
+		return nil, true, nil
+	}
+	return words, true, nil
+}
+
+// combine will call fn with the words followed by every combination of words from the wordlists held in memory,
+// returning false if fn asked to stop
+func (p *PayloadSet) combine(words []string, fn func(words []string) bool) bool {
+	if len(words) == len(p.bindings) {
+		return fn(slices.Clone(words))
+	}
+	for _, word := range p.inner[len(words)-1] {
+		if !p.combine(append(words, word), fn) {
+			return false
+		}
+	}
+	return true
+}
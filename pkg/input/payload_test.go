@@ -0,0 +1,225 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  Binding
+	}{
+		{
+			name:  "should bind a wordlist without a keyword to the default keyword",
+			value: "/path/to/words.txt",
+			want:  Binding{Filepath: "/path/to/words.txt", Keyword: client.Keyword},
+		},
+		{
+			name:  "should bind a wordlist to the given keyword",
+			value: "/path/to/users.txt:USER",
+			want:  Binding{Filepath: "/path/to/users.txt", Keyword: "USER"},
+		},
+		{
+			name:  "should not treat a lowercase suffix as a keyword",
+			value: "C:words.txt",
+			want:  Binding{Filepath: "C:words.txt", Keyword: client.Keyword},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseBinding(tt.value); got != tt.want {
+				t.Errorf("ParseBinding() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePayloadMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      PayloadMode
+		wantError bool
+	}{
+		{name: "should default to clusterbomb", value: "", want: PayloadClusterbomb},
+		{name: "should parse pitchfork", value: "Pitchfork", want: PayloadPitchfork},
+		{name: "should reject an unknown mode", value: "sniper", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePayloadMode(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParsePayloadMode() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePayloadMode() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadSet_Stream(t *testing.T) {
+	dir := t.TempDir()
+	users := filepath.Join(dir, "users.txt")
+	passwords := filepath.Join(dir, "passwords.txt")
+	if err := os.WriteFile(users, []byte("admin\nroot\n"), 0600); err != nil {
+		t.Fatalf("failed to create users file: %v", err)
+	}
+	if err := os.WriteFile(passwords, []byte("hunter2\nletmein\npassword\n"), 0600); err != nil {
+		t.Fatalf("failed to create passwords file: %v", err)
+	}
+	bindings := []Binding{{Filepath: users, Keyword: "USER"}, {Filepath: passwords, Keyword: "PASS"}}
+
+	tests := []struct {
+		name string
+		mode PayloadMode
+		want []map[string]string
+	}{
+		{
+			name: "should combine every word in clusterbomb mode",
+			mode: PayloadClusterbomb,
+			want: []map[string]string{
+				{"USER": "admin", "PASS": "hunter2"},
+				{"USER": "admin", "PASS": "letmein"},
+				{"USER": "admin", "PASS": "password"},
+				{"USER": "root", "PASS": "hunter2"},
+				{"USER": "root", "PASS": "letmein"},
+				{"USER": "root", "PASS": "password"},
+			},
+		},
+		{
+			name: "should combine words line by line in pitchfork mode",
+			mode: PayloadPitchfork,
+			want: []map[string]string{
+				{"USER": "admin", "PASS": "hunter2"},
+				{"USER": "root", "PASS": "letmein"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads, err := NewPayloadSet(bindings, tt.mode, Options{})
+			if err != nil {
+				t.Fatalf("NewPayloadSet returned an unexpected error: %v", err)
+			}
+			var got []map[string]string
+			err = payloads.Stream("http://example.com", func(request client.Request) bool {
+				if request.Subdomain != request.Payload["USER"] {
+					t.Errorf("Expected subdomain to be the word from the first wordlist, but got %q", request.Subdomain)
+				}
+				got = append(got, request.Payload)
+				return true
+			})
+			if err != nil {
+				t.Fatalf("Stream returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("payloads = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("should stop streaming when asked to", func(t *testing.T) {
+		payloads, err := NewPayloadSet(bindings, PayloadClusterbomb, Options{})
+		if err != nil {
+			t.Fatalf("NewPayloadSet returned an unexpected error: %v", err)
+		}
+
+		var streamed int
+		err = payloads.Stream("http://example.com", func(_ client.Request) bool {
+			streamed++
+			return streamed < 2
+		})
+		if err != nil {
+			t.Fatalf("Stream returned an unexpected error: %v", err)
+		}
+		if streamed != 2 {
+			t.Errorf("Expected 2 requests to be streamed, but got %d", streamed)
+		}
+	})
+
+	t.Run("should drop the whole row in pitchfork mode when a line is dropped from any wordlist", func(t *testing.T) {
+		users := filepath.Join(dir, "users-blank.txt")
+		passwords := filepath.Join(dir, "passwords-comment.txt")
+		if err := os.WriteFile(users, []byte("alice\n\nbob\ncarol\nalice\n"), 0600); err != nil {
+			t.Fatalf("failed to create users file: %v", err)
+		}
+		if err := os.WriteFile(passwords, []byte("pw1\npw2\npw3\n# comment\npw1\n"), 0600); err != nil {
+			t.Fatalf("failed to create passwords file: %v", err)
+		}
+		options := Options{SkipBlank: true, SkipComments: true, Dedupe: true}
+		payloads, err := NewPayloadSet([]Binding{{Filepath: users, Keyword: "USER"}, {Filepath: passwords, Keyword: "PASS"}},
+			PayloadPitchfork, options)
+		if err != nil {
+			t.Fatalf("NewPayloadSet returned an unexpected error: %v", err)
+		}
+
+		var got []map[string]string
+		err = payloads.Stream("http://example.com", func(request client.Request) bool {
+			got = append(got, request.Payload)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Stream returned an unexpected error: %v", err)
+		}
+		want := []map[string]string{{"USER": "alice", "PASS": "pw1"}, {"USER": "bob", "PASS": "pw3"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("payloads = %v; want %v", got, want)
+		}
+		if dropped := payloads.Dropped(); dropped != (Dropped{Comments: 1, Blank: 1, Duplicates: 1}) {
+			t.Errorf("Dropped() = %+v; want one of each", dropped)
+		}
+	})
+
+	t.Run("should only mutate and expand the first wordlist in clusterbomb mode", func(t *testing.T) {
+		options := Options{Extensions: []string{"php"}, AppendExtensions: true}
+		payloads, err := NewPayloadSet(bindings[:2], PayloadClusterbomb, options)
+		if err != nil {
+			t.Fatalf("NewPayloadSet returned an unexpected error: %v", err)
+		}
+
+		var got []string
+		err = payloads.Stream("http://example.com", func(request client.Request) bool {
+			got = append(got, request.Payload["USER"]+":"+request.Payload["PASS"])
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Stream returned an unexpected error: %v", err)
+		}
+		want := []string{
+			"admin:hunter2", "admin:letmein", "admin:password",
+			"admin.php:hunter2", "admin.php:letmein", "admin.php:password",
+			"root:hunter2", "root:letmein", "root:password",
+			"root.php:hunter2", "root.php:letmein", "root.php:password",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("payloads = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("should reject mutations and extensions in pitchfork mode", func(t *testing.T) {
+		for _, options := range []Options{{Extensions: []string{"php"}}, {Mutations: Chain{Builtin["case"]}}} {
+			if _, err := NewPayloadSet(bindings, PayloadPitchfork, options); err == nil {
+				t.Errorf("Expected error for %+v, but got nil", options)
+			}
+		}
+	})
+
+	t.Run("should not bind the same keyword twice", func(t *testing.T) {
+		_, err := NewPayloadSet([]Binding{{Filepath: users, Keyword: "USER"}, {Filepath: passwords, Keyword: "USER"}},
+			PayloadClusterbomb, Options{})
+		if err == nil {
+			t.Errorf("Expected error, but got nil")
+		}
+	})
+}
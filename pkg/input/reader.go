@@ -0,0 +1,153 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+)
+
+// Stdin is the filepath used to read a wordlist from standard input instead of a file
+const Stdin = "-"
+
+// Reader reads the words of a wordlist one at a time rather than loading the whole file into memory, each line is
+// mutated and expanded with the options as it is read
+type Reader struct {
+	Options
+	file    io.ReadCloser
+	scanner *bufio.Scanner
+	pending []string
+	seen    map[uint64]struct{}
+	dropped Dropped
+}
+
+// Dropped counts the lines and words that were dropped whilst reading a wordlist
+type Dropped struct {
+	Comments   int
+	Blank      int
+	Duplicates int
+}
+
+// Total returns how many lines and words were dropped altogether
+func (d Dropped) Total() int {
+	return d.Comments + d.Blank + d.Duplicates
+}
+
+// Add returns the sum of both counts
+func (d Dropped) Add(other Dropped) Dropped {
+	return Dropped{
+		Comments:   d.Comments + other.Comments,
+		Blank:      d.Blank + other.Blank,
+		Duplicates: d.Duplicates + other.Duplicates,
+	}
+}
+
+// Open returns a reader for the wordlist at the given filepath, or standard input if the filepath is -
+func (o Options) Open(filepath string) (*Reader, error) {
+	if filepath != Stdin {
+		_, err := isFileReadable(filepath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if file is readable: %w", err)
+		}
+	}
+	file, err := openFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		Options: o,
+		file:    file,
+		scanner: bufio.NewScanner(file),
+	}, nil
+}
+
+// Next returns the next word from the wordlist, once there are no words left or reading fails it returns false and Err
+// should be checked
+func (r *Reader) Next() (string, bool) {
+	for len(r.pending) == 0 {
+		line, ok := r.line()
+		if !ok {
+			return "", false
+		}
+		line, ok = r.normalise(line, &r.dropped)
+		if !ok {
+			continue
+		}
+		for _, mutated := range r.Mutations.Mutate(line) {
+			for _, word := range r.expand(mutated) {
+				if r.Dedupe && r.duplicate(word) {
+					r.dropped.Duplicates++
+					continue
+				}
+				r.pending = append(r.pending, word)
+			}
+		}
+	}
+	word := r.pending[0]
+	r.pending = r.pending[1:]
+	return word, true
+}
+
+// Dropped returns how many lines and words have been dropped so far
+func (r *Reader) Dropped() Dropped {
+	return r.dropped
+}
+
+// line returns the next line of the wordlist exactly as it was read, without normalising, mutating or expanding it
+func (r *Reader) line() (string, bool) {
+	if !r.scanner.Scan() {
+		return "", false
+	}
+	return r.scanner.Text(), true
+}
+
+// normalise will clean up the line with the options, returning false and counting it as dropped if the line should be
+// dropped
+func (o Options) normalise(line string, dropped *Dropped) (string, bool) {
+	if o.Trim {
+		line = strings.TrimSpace(line)
+	}
+	if o.SkipComments && strings.HasPrefix(strings.TrimSpace(line), "#") {
+		dropped.Comments++
+		return "", false
+	}
+	if o.StripLeadingSlashes {
+		line = strings.TrimLeft(line, "/")
+	}
+	if o.SkipBlank && strings.TrimSpace(line) == "" {
+		dropped.Blank++
+		return "", false
+	}
+	return line, true
+}
+
+// duplicate returns whether the word has already been read. Only a 64 bit hash of each word is kept rather than the
+// word itself, so that deduping a list of millions of words does not need to hold every word in memory, a collision
+// between two different words is unlikely enough for a wordlist that it is not worth checking for.
+func (r *Reader) duplicate(word string) bool {
+	if r.seen == nil {
+		r.seen = make(map[uint64]struct{})
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(word))
+	sum := h.Sum64()
+	if _, ok := r.seen[sum]; ok {
+		return true
+	}
+	r.seen[sum] = struct{}{}
+	return false
+}
+
+// Err returns the first error that occurred whilst reading the wordlist
+func (r *Reader) Err() error {
+	if err := r.scanner.Err(); err != nil {
+		return fmt.Errorf("error reading wordlist: %w", err)
+	}
+	return nil
+}
+
+// Close will close the file the wordlist is being read from
+func (r *Reader) Close() {
+	closeFile(r.file)
+}
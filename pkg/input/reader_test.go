@@ -0,0 +1,102 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReader_Next(t *testing.T) {
+	t.Run("should read each word expanded with the options", func(t *testing.T) {
+		mockFile := filepath.Join(t.TempDir(), "words.txt")
+		if err := os.WriteFile(mockFile, []byte("admin\nindex.%ext%\n"), 0600); err != nil {
+			t.Fatalf("failed to create mock file: %v", err)
+		}
+
+		reader, err := Options{Extensions: []string{"php", "bak"}}.Open(mockFile)
+		if err != nil {
+			t.Fatalf("Open returned an unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		var got []string
+		for word, ok := reader.Next(); ok; word, ok = reader.Next() {
+			got = append(got, word)
+		}
+		if err := reader.Err(); err != nil {
+			t.Errorf("Err returned an unexpected error: %v", err)
+		}
+
+		want := []string{"admin", "index.php", "index.bak"}
+		if !slices.Equal(got, want) {
+			t.Errorf("words = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("should return an error if the file does not exist", func(t *testing.T) {
+		_, err := Options{}.Open(filepath.Join(t.TempDir(), "missing.txt"))
+		if err == nil {
+			t.Errorf("Expected error, but got nil")
+		}
+	})
+}
+
+func TestReader_normalise(t *testing.T) {
+	content := "# a comment\n\n  admin  \n/login\nadmin\n//\n#fragment\n"
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+		dropped Dropped
+	}{
+		{
+			name:    "should keep every line verbatim without any options",
+			options: Options{},
+			want:    []string{"# a comment", "", "  admin  ", "/login", "admin", "//", "#fragment"},
+		},
+		{
+			name: "should drop comments, blank lines and duplicates",
+			options: Options{
+				SkipComments:        true,
+				SkipBlank:           true,
+				Trim:                true,
+				StripLeadingSlashes: true,
+				Dedupe:              true,
+			},
+			want:    []string{"admin", "login"},
+			dropped: Dropped{Comments: 2, Blank: 2, Duplicates: 1},
+		},
+		{
+			name:    "should only trim and strip slashes",
+			options: Options{Trim: true, StripLeadingSlashes: true},
+			want:    []string{"# a comment", "", "admin", "login", "admin", "", "#fragment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFile := filepath.Join(t.TempDir(), "words.txt")
+			if err := os.WriteFile(mockFile, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to create mock file: %v", err)
+			}
+
+			reader, err := tt.options.Open(mockFile)
+			if err != nil {
+				t.Fatalf("Open returned an unexpected error: %v", err)
+			}
+			defer reader.Close()
+
+			var got []string
+			for word, ok := reader.Next(); ok; word, ok = reader.Next() {
+				got = append(got, word)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("words = %q; want %q", got, tt.want)
+			}
+			if reader.Dropped() != tt.dropped {
+				t.Errorf("Dropped() = %+v; want %+v", reader.Dropped(), tt.dropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Mutator turns a word into the words that should be requested in its place, mutators are applied to every word as the
+// wordlist is read
+type Mutator interface {
+	Mutate(word string) []string
+}
+
+// MutatorFunc is an adapter to allow a plain function to be used as a mutator
+type MutatorFunc func(word string) []string
+
+// Mutate calls the function with the word
+func (f MutatorFunc) Mutate(word string) []string {
+	return f(word)
+}
+
+// Chain applies each mutator in turn, every word produced by one mutator is passed through the next so that rule files
+// and built in mutations can be combined
+type Chain []Mutator
+
+// Mutate returns every word produced by the chain without duplicates, an empty chain keeps the word as is
+func (c Chain) Mutate(word string) []string {
+	words := []string{word}
+	for _, mutator := range c {
+		var next []string
+		seen := make(map[string]struct{})
+		for _, w := range words {
+			for _, mutated := range mutator.Mutate(w) {
+				if _, ok := seen[mutated]; ok || mutated == "" {
+					continue
+				}
+				seen[mutated] = struct{}{}
+				next = append(next, mutated)
+			}
+		}
+		words = next
+	}
+	return words
+}
+
+// operation is a single function of a rule that changes the word
+type operation func(word []rune) []rune
+
+// Rule is a hashcat style rule, a line of functions that are applied to the word from left to right
+type Rule []operation
+
+// Apply returns the word with every function of the rule applied to it
+func (r Rule) Apply(word string) string {
+	w := []rune(word)
+	for _, op := range r {
+		w = op(w)
+	}
+	return string(w)
+}
+
+// RuleSet produces one word for every rule, like a hashcat rule file. A rule file should contain the : rule to keep the
+// original word.
+type RuleSet []Rule
+
+// Mutate returns the word produced by each rule
+func (rs RuleSet) Mutate(word string) []string {
+	words := make([]string, 0, len(rs))
+	for _, rule := range rs {
+		words = append(words, rule.Apply(word))
+	}
+	return words
+}
+
+// ParseRule will parse a single line of hashcat style rule functions, spaces between functions are ignored. The
+// supported functions are : l u c C t TN r d f { } $X ^X [ ] DN 'N sXY @X zN and ZN, where N is a position of 0-9 or
+// A-Z for 10-35.
+func ParseRule(line string) (Rule, error) {
+	var rule Rule
+	in := []rune(line)
+	for i := 0; i < len(in); i++ {
+		fn := in[i]
+		args, arity := in[i+1:], ruleArity(fn)
+		if arity < 0 {
+			return nil, fmt.Errorf("unsupported rule function %q in rule: %s", fn, line)
+		}
+		if len(args) < arity {
+			return nil, fmt.Errorf("rule function %q is missing an argument in rule: %s", fn, line)
+		}
+		op, err := ruleOperation(fn, args[:arity])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing rule %s: %w", line, err)
+		}
+		if op != nil {
+			rule = append(rule, op)
+		}
+		i += arity
+	}
+	return rule, nil
+}
+
+// ParseRules will parse a rule for every line, blank lines and lines starting with # are skipped
+func ParseRules(r io.Reader) (RuleSet, error) {
+	var rules RuleSet
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := ParseRule(line)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// LoadRuleFile will read and parse the rules in the given file
+func LoadRuleFile(filepath string) (RuleSet, error) {
+	file, err := os.Open(filepath) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("error opening rule file: %w", err)
+	}
+	defer closeFile(file)
+
+	rules, err := ParseRules(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rule file %s: %w", filepath, err)
+	}
+	return rules, nil
+}
+
+// ruleArity returns how many arguments the rule function takes, or -1 if the function is not supported
+func ruleArity(fn rune) int {
+	switch fn {
+	case ':', 'l', 'u', 'c', 'C', 't', 'r', 'd', 'f', '{', '}', '[', ']', ' ':
+		return 0
+	case 'T', '$', '^', 'D', '\'', '@', 'z', 'Z':
+		return 1
+	case 's':
+		return 2
+	default:
+		return -1
+	}
+}
+
+// ruleOperation returns the operation for the rule function with the given arguments, functions that do nothing return
+// a nil operation
+func ruleOperation(fn rune, args []rune) (operation, error) {
+	switch fn {
+	case ':', ' ':
+		return nil, nil
+	case 'l':
+		return func(w []rune) []rune { return []rune(strings.ToLower(string(w))) }, nil
+	case 'u':
+		return func(w []rune) []rune { return []rune(strings.ToUpper(string(w))) }, nil
+	case 'c':
+		return func(w []rune) []rune { return capitalize(w, unicode.ToUpper, unicode.ToLower) }, nil
+	case 'C':
+		return func(w []rune) []rune { return capitalize(w, unicode.ToLower, unicode.ToUpper) }, nil
+	case 't':
+		return func(w []rune) []rune {
+			for i := range w {
+				w[i] = toggle(w[i])
+			}
+			return w
+		}, nil
+	case 'r':
+		return func(w []rune) []rune {
+			reversed := make([]rune, len(w))
+			for i, r := range w {
+				reversed[len(w)-1-i] = r
+			}
+			return reversed
+		}, nil
+	case 'd':
+		return func(w []rune) []rune { return append(w, w...) }, nil
+	case 'f':
+		return func(w []rune) []rune {
+			reflected := append([]rune{}, w...)
+			for i := len(w) - 1; i >= 0; i-- {
+				reflected = append(reflected, w[i])
+			}
+			return reflected
+		}, nil
+	case '{':
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return append(w[1:], w[0])
+		}, nil
+	case '}':
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return append([]rune{w[len(w)-1]}, w[:len(w)-1]...)
+		}, nil
+	case '[':
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return w[1:]
+		}, nil
+	case ']':
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return w[:len(w)-1]
+		}, nil
+	case '$':
+		return func(w []rune) []rune { return append(w, args[0]) }, nil
+	case '^':
+		return func(w []rune) []rune { return append([]rune{args[0]}, w...) }, nil
+	case 's':
+		return func(w []rune) []rune {
+			for i := range w {
+				if w[i] == args[0] {
+					w[i] = args[1]
+				}
+			}
+			return w
+		}, nil
+	case '@':
+		return func(w []rune) []rune {
+			kept := w[:0]
+			for _, r := range w {
+				if r != args[0] {
+					kept = append(kept, r)
+				}
+			}
+			return kept
+		}, nil
+	}
+
+	n, err := rulePosition(args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case 'T':
+		return func(w []rune) []rune {
+			if n < len(w) {
+				w[n] = toggle(w[n])
+			}
+			return w
+		}, nil
+	case 'D':
+		return func(w []rune) []rune {
+			if n < len(w) {
+				return append(w[:n], w[n+1:]...)
+			}
+			return w
+		}, nil
+	case '\'':
+		return func(w []rune) []rune { return w[:min(n, len(w))] }, nil
+	case 'z':
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return append([]rune(strings.Repeat(string(w[0]), n)), w...)
+		}, nil
+	default:
+		return func(w []rune) []rune {
+			if len(w) == 0 {
+				return w
+			}
+			return append(w, []rune(strings.Repeat(string(w[len(w)-1]), n))...)
+		}, nil
+	}
+}
+
+// rulePosition converts a position argument of 0-9 or A-Z into a number from 0 to 35
+func rulePosition(r rune) (int, error) {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0'), nil
+	case r >= 'A' && r <= 'Z':
+		return int(r-'A') + 10, nil
+	default:
+		return 0, fmt.Errorf("invalid position %q", r)
+	}
+}
+
+// capitalize will apply first to the first letter of the word and rest to the remaining letters
+func capitalize(w []rune, first, rest func(rune) rune) []rune {
+	for i := range w {
+		if i == 0 {
+			w[i] = first(w[i])
+		} else {
+			w[i] = rest(w[i])
+		}
+	}
+	return w
+}
+
+// toggle will swap the case of the letter
+func toggle(r rune) rune {
+	if unicode.IsUpper(r) {
+		return unicode.ToLower(r)
+	}
+	return unicode.ToUpper(r)
+}
+
+// leetRule replaces common letters with the numbers that look like them
+var leetRule = Rule{func(w []rune) []rune {
+	return []rune(strings.NewReplacer("a", "4", "e", "3", "i", "1", "o", "0", "s", "5", "t", "7").Replace(string(w)))
+}}
+
+// Builtin mutators can be selected by name, each keeps the original word alongside its mutations
+var Builtin = map[string]Mutator{
+	"case":     RuleSet{Rule{}, mustParseRule("l"), mustParseRule("u"), mustParseRule("c")},
+	"leet":     RuleSet{Rule{}, leetRule},
+	"plural":   MutatorFunc(pluralise),
+	"versions": Affixes{Suffixes: versionSuffixes(3)},
+	"dates":    Affixes{Suffixes: dateSuffixes(time.Now().Year()-4, time.Now().Year())},
+}
+
+// ParseBuiltin will return the built in mutators with the given names, in the order they were given
+func ParseBuiltin(names []string) (Chain, error) {
+	var chain Chain
+	for _, name := range names {
+		mutator, ok := Builtin[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported mutation: %s", name)
+		}
+		chain = append(chain, mutator)
+	}
+	return chain, nil
+}
+
+// mustParseRule is used for the built in rules, which are known to be valid
+func mustParseRule(line string) Rule {
+	rule, err := ParseRule(line)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// Affixes keeps the word and adds a word for each prefix and each suffix
+type Affixes struct {
+	Prefixes []string
+	Suffixes []string
+}
+
+// Mutate returns the word followed by the word with each prefix and then each suffix
+func (a Affixes) Mutate(word string) []string {
+	words := make([]string, 0, len(a.Prefixes)+len(a.Suffixes)+1)
+	words = append(words, word)
+	for _, prefix := range a.Prefixes {
+		words = append(words, prefix+word)
+	}
+	for _, suffix := range a.Suffixes {
+		words = append(words, word+suffix)
+	}
+	return words
+}
+
+// pluralise keeps the word and adds its plural, following the most common english rules
+func pluralise(word string) []string {
+	lower := strings.ToLower(word)
+	switch {
+	case lower == "":
+		return []string{word}
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return []string{word, word + "es"}
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return []string{word, word[:len(word)-1] + "ies"}
+	default:
+		return []string{word, word + "s"}
+	}
+}
+
+// versionSuffixes returns the suffixes commonly used for versions of a file or endpoint, i.e. 1, v1, _v1 and -v1
+func versionSuffixes(versions int) []string {
+	var suffixes []string
+	for _, format := range []string{"%d", "v%d", "_v%d", "-v%d", ".%d"} {
+		for v := 1; v <= versions; v++ {
+			suffixes = append(suffixes, fmt.Sprintf(format, v))
+		}
+	}
+	return suffixes
+}
+
+// dateSuffixes returns every year between from and to, on their own and separated with an underscore or dash
+func dateSuffixes(from, to int) []string {
+	var suffixes []string
+	for _, separator := range []string{"", "_", "-"} {
+		for year := from; year <= to; year++ {
+			suffixes = append(suffixes, separator+strconv.Itoa(year))
+		}
+	}
+	return suffixes
+}
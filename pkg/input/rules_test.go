@@ -0,0 +1,158 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		word      string
+		want      string
+		wantError bool
+	}{
+		{name: "should keep the word with the noop rule", rule: ":", word: "Admin", want: "Admin"},
+		{name: "should lowercase the word", rule: "l", word: "Admin", want: "admin"},
+		{name: "should uppercase the word", rule: "u", word: "admin", want: "ADMIN"},
+		{name: "should capitalize the word", rule: "c", word: "aDMIN", want: "Admin"},
+		{name: "should toggle the case of the word", rule: "t", word: "aDmin", want: "AdMIN"},
+		{name: "should toggle the case at a position", rule: "T1", word: "admin", want: "aDmin"},
+		{name: "should reverse the word", rule: "r", word: "admin", want: "nimda"},
+		{name: "should duplicate the word", rule: "d", word: "ab", want: "abab"},
+		{name: "should append and prepend characters", rule: "^_ $1 $2", word: "admin", want: "_admin12"},
+		{name: "should replace characters", rule: "sa4 si1", word: "admin", want: "4dm1n"},
+		{name: "should truncate the word", rule: "'3", word: "admin", want: "adm"},
+		{name: "should delete a character", rule: "D0", word: "admin", want: "dmin"},
+		{name: "should purge a character", rule: "@n", word: "banana", want: "baaa"},
+		{name: "should chain functions from left to right", rule: "c $s", word: "user", want: "Users"},
+		{name: "should reject an unsupported function", rule: "X", wantError: true},
+		{name: "should reject a missing argument", rule: "$", wantError: true},
+		{name: "should reject an invalid position", rule: "T!", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseRule() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			if got := rule.Apply(tt.word); got != tt.want {
+				t.Errorf("Apply() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	t.Run("should skip comments and blank lines", func(t *testing.T) {
+		rules, err := ParseRules(strings.NewReader("# keep the word\n:\n\nu\n"))
+		if err != nil {
+			t.Fatalf("ParseRules returned an unexpected error: %v", err)
+		}
+
+		got := rules.Mutate("admin")
+		want := []string{"admin", "ADMIN"}
+		if !slices.Equal(got, want) {
+			t.Errorf("Mutate() = %v; want %v", got, want)
+		}
+	})
+}
+
+func TestChain_Mutate(t *testing.T) {
+	t.Run("should pass every word through the next mutator without duplicates", func(t *testing.T) {
+		chain := Chain{
+			RuleSet{mustParseRule(":"), mustParseRule("l")},
+			Affixes{Suffixes: []string{"_old"}},
+		}
+
+		got := chain.Mutate("admin")
+		want := []string{"admin", "admin_old"}
+		if !slices.Equal(got, want) {
+			t.Errorf("Mutate() = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("should keep the word with an empty chain", func(t *testing.T) {
+		var chain Chain
+		if got := chain.Mutate("admin"); !slices.Equal(got, []string{"admin"}) {
+			t.Errorf("Mutate() = %v; want [admin]", got)
+		}
+	})
+}
+
+func Test_pluralise(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "user", want: "users"},
+		{word: "box", want: "boxes"},
+		{word: "category", want: "categories"},
+		{word: "key", want: "keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should pluralise "+tt.word, func(t *testing.T) {
+			got := pluralise(tt.word)
+			if !slices.Equal(got, []string{tt.word, tt.want}) {
+				t.Errorf("pluralise() = %v; want [%s %s]", got, tt.word, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBuiltin(t *testing.T) {
+	t.Run("should reject an unknown mutation", func(t *testing.T) {
+		_, err := ParseBuiltin([]string{"case", "rot13"})
+		if err == nil {
+			t.Errorf("Expected error, but got nil")
+		}
+	})
+
+	t.Run("should add version suffixes", func(t *testing.T) {
+		chain, err := ParseBuiltin([]string{"versions"})
+		if err != nil {
+			t.Fatalf("ParseBuiltin returned an unexpected error: %v", err)
+		}
+		got := chain.Mutate("api")
+		for _, want := range []string{"api", "api1", "apiv2", "api_v3"} {
+			if !slices.Contains(got, want) {
+				t.Errorf("Expected %q in %v", want, got)
+			}
+		}
+	})
+}
+
+func TestWordList_NewWordList_Mutations(t *testing.T) {
+	t.Run("should mutate each word before expanding extensions", func(t *testing.T) {
+		mockFile := filepath.Join(t.TempDir(), "words.txt")
+		if err := os.WriteFile(mockFile, []byte("admin.%ext%\n"), 0600); err != nil {
+			t.Fatalf("failed to create mock file: %v", err)
+		}
+
+		wl := &WordList{Options: Options{
+			Extensions: []string{"php"},
+			Mutations:  Chain{RuleSet{mustParseRule(":"), mustParseRule("c")}},
+		}}
+		if err := wl.NewWordList(mockFile); err != nil {
+			t.Fatalf("NewWordList returned an unexpected error: %v", err)
+		}
+
+		var got []string
+		for _, word := range wl.data {
+			got = append(got, string(word))
+		}
+		want := []string{"admin.php", "Admin.php"}
+		if !slices.Equal(got, want) {
+			t.Errorf("words = %v; want %v", got, want)
+		}
+	})
+}
@@ -9,11 +9,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// Dispatcher default implementation of the job dispatcher, includes the queue and the workers
+// maxRequeues is how many times a job is requeued because the target asked to slow down before giving up on it
+const maxRequeues = 5
+
+// Dispatcher default implementation of the job dispatcher, includes the queue and the workers. The throttle is shared by
+// every worker and can be left nil to run jobs as fast as the workers allow.
 type Dispatcher struct {
 	WorkerPool chan chan *Job
 	JobQueue   chan *Job
+	Results    chan Result
 	Workers    []*Worker
+	Throttle   *Throttle
 	wg         *sync.WaitGroup
 	batchSize  int
 }
@@ -22,11 +28,13 @@ type Dispatcher struct {
 func NewDispatcher(numWorkers, queueSize int) *Dispatcher {
 	workerPool := make(chan chan *Job, numWorkers)
 	jobQueue := make(chan *Job, queueSize)
+	results := make(chan Result, numWorkers)
 	workers := make([]*Worker, numWorkers)
 
 	return &Dispatcher{
 		WorkerPool: workerPool,
 		JobQueue:   jobQueue,
+		Results:    results,
 		Workers:    workers,
 		wg:         &sync.WaitGroup{},
 		batchSize:  300, // hardcode limit of 300 batch so that it doesn't fail overload the execution
@@ -39,8 +47,9 @@ func (d *Dispatcher) Run(r *client.Requester) {
 		worker := &Worker{
 			ID:         i,
 			JobChannel: make(chan *Job),
+			Results:    d.Results,
 			Requester:  r,
-			wg:         d.wg,
+			Throttle:   d.Throttle,
 		}
 		worker.Start()
 		d.WorkerPool <- worker.JobChannel
@@ -88,15 +97,56 @@ func (d *Dispatcher) dispatchBatch(batch []*Job) {
 	}
 }
 
-// Submit adds a job to the job queue
+// Submit adds a job to the job queue, blocking if the queue is full
 func (d *Dispatcher) Submit(job *Job) {
 	log.Debugf("Submitting job: %v", job.ID)
 	d.wg.Add(1)
 	d.JobQueue <- job
 }
 
-// Wait blocks until all jobs are processed
+// SubmitStream adds every job received from the channel to the job queue in the background until the channel is closed.
+// The stream is counted as pending straight away so that it is safe to call whilst the dispatcher is running, including
+// from within Collect, and Wait will not return before the channel is closed. Jobs are only taken from the channel when
+// there is room on the queue, so whatever is producing them is held back.
+func (d *Dispatcher) SubmitStream(jobs <-chan *Job) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for job := range jobs {
+			d.Submit(job)
+		}
+	}()
+}
+
+// Collect calls handle for every result published by the workers until the results channel is closed by Wait, a job
+// is only marked as done once handle has returned so any jobs submitted by handle are always waited for. Nothing will
+// finish unless the results are collected. Jobs the target asked to slow down for are requeued rather than handled, up
+// to a limit after which the result is handled as is.
+func (d *Dispatcher) Collect(handle func(result Result)) {
+	for result := range d.Results {
+		if d.Throttle.Observe(&result.Response) && result.Job.Requeued < maxRequeues {
+			d.requeue(result.Job)
+			continue
+		}
+		handle(result)
+		d.wg.Done()
+	}
+}
+
+// requeue puts the job back on the queue in the background to be run again once the throttle allows, the job is still
+// pending so Wait will not return before it has been run again
+func (d *Dispatcher) requeue(job *Job) {
+	job.Requeued++
+	log.Debugf("Requeueing job: %v", job.ID)
+	go func() {
+		d.JobQueue <- job
+	}()
+}
+
+// Wait blocks until all jobs are processed and their results collected, the results channel is closed once every job
+// has published its result so that Collect can return
 func (d *Dispatcher) Wait() {
 	d.wg.Wait()
 	close(d.JobQueue)
+	close(d.Results)
 }
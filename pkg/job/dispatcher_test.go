@@ -0,0 +1,126 @@
+package job
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestDispatcher_SubmitStream(t *testing.T) {
+	t.Run("should wait for jobs streamed whilst collecting results", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := NewDispatcher(2, 1)
+		dispatcher.Submit(NewJob(0, client.Request{URL: mockServer.URL, Subdomain: "root"}))
+		dispatcher.Run(requester)
+
+		var collected []Result
+		go dispatcher.Collect(func(result Result) {
+			collected = append(collected, result)
+			if result.Job.Depth < 2 {
+				children := make(chan *Job, 3)
+				for i := range 3 {
+					child := NewJob(i+1, client.Request{URL: mockServer.URL, Subdomain: "child"})
+					child.Depth = result.Job.Depth + 1
+					children <- child
+				}
+				close(children)
+				dispatcher.SubmitStream(children)
+			}
+		})
+		dispatcher.Wait()
+
+		// one root job, three children and then three children for each of those
+		if len(collected) != 13 {
+			t.Errorf("Expected 13 results, but got %d", len(collected))
+		}
+	})
+
+	t.Run("should run every job from the stream with a queue smaller than the stream", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := NewDispatcher(2, 1)
+		dispatcher.Run(requester)
+
+		var collected int
+		go dispatcher.Collect(func(_ Result) {
+			collected++
+		})
+
+		jobs := make(chan *Job)
+		dispatcher.SubmitStream(jobs)
+		go func() {
+			defer close(jobs)
+			for i := range 20 {
+				jobs <- NewJob(i, client.Request{URL: mockServer.URL, Subdomain: "word"})
+			}
+		}()
+		dispatcher.Wait()
+
+		if collected != 20 {
+			t.Errorf("Expected 20 results, but got %d", collected)
+		}
+	})
+}
+
+func TestDispatcher_Collect_Requeue(t *testing.T) {
+	t.Run("should requeue jobs the target asked to slow down for", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) <= 2 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		requester := &client.Requester{
+			Timeout: 1 * time.Second,
+			Method:  "GET",
+		}
+
+		dispatcher := NewDispatcher(1, 1)
+		dispatcher.Throttle = NewThrottle(0, 0, 0)
+		dispatcher.Throttle.Adaptive = true
+		dispatcher.Throttle.backoff = 10 * time.Millisecond
+		dispatcher.Submit(NewJob(0, client.Request{URL: mockServer.URL, Subdomain: "word"}))
+		dispatcher.Run(requester)
+
+		var collected []Result
+		go dispatcher.Collect(func(result Result) {
+			collected = append(collected, result)
+		})
+		dispatcher.Wait()
+
+		if len(collected) != 1 {
+			t.Fatalf("Expected 1 result, but got %d", len(collected))
+		}
+		if collected[0].Response.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, but got %d", collected[0].Response.StatusCode)
+		}
+		if collected[0].Job.Requeued != 2 {
+			t.Errorf("Expected job to be requeued twice, but got %d", collected[0].Job.Requeued)
+		}
+	})
+}
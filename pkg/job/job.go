@@ -2,29 +2,36 @@
 package job
 
 import (
-	"log"
-
 	"github.com/ch55secake/dizzy/pkg/client"
 )
 
 // Task represents the function type for job logic and also what will be done
-type Task func(client *client.Requester)
+type Task func(client *client.Requester) (client.Response, error)
 
-// Job represents a unit of work with custom logic
+// Job represents a unit of work with custom logic, depth is how many directories deep the request is from where the
+// scan started and requeued is how many times it has been run again because the target asked to slow down
 type Job struct {
-	ID      int
-	Execute Task
+	ID       int
+	Depth    int
+	Requeued int
+	Request  client.Request
+	Execute  Task
+}
+
+// Result is what a worker publishes once a job has been executed, either the response or the error that occurred
+type Result struct {
+	Job      *Job
+	Response client.Response
+	Err      error
 }
 
 // NewJob will return a job with a random id and a given request, this job will then be added to the queue
 func NewJob(id int, request client.Request) *Job {
 	return &Job{
-		ID: id,
-		Execute: func(client *client.Requester) {
-			_, err := client.MakeRequest(request)
-			if err != nil {
-				log.Printf("Error creating new job with id: %d and err: %v", id, err)
-			}
+		ID:      id,
+		Request: request,
+		Execute: func(client *client.Requester) (client.Response, error) {
+			return client.MakeRequest(request)
 		},
 	}
 }
@@ -0,0 +1,159 @@
+package job
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// backoffRate is the requests per second a scan without a rate limit drops to when the target first asks to slow down
+	backoffRate = 10
+	// minRate is the slowest the rate is halved down to, unless the configured rate is already slower than this
+	minRate = 1
+	// minBackoff is how long every worker pauses the first time the target asks to slow down without a Retry-After
+	minBackoff = 1 * time.Second
+	// maxBackoff is the longest every worker pauses for without a Retry-After, the pause doubles each time up to this
+	maxBackoff = 60 * time.Second
+	// rampUpAfter is how many responses in a row need to be fine before the rate is doubled back towards the limit
+	rampUpAfter = 20
+)
+
+// Throttle limits how fast jobs are started across every worker, with a shared requests per second limit and a delay
+// that each worker waits before every job it starts. When adaptive, the throttle also slows every worker down when the
+// target responds with 429 or 503 and speeds them back up once the target has recovered.
+type Throttle struct {
+	Adaptive bool
+
+	limiter *rate.Limiter
+	limit   rate.Limit
+	delay   time.Duration
+	jitter  time.Duration
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	backoff     time.Duration
+	successes   int
+}
+
+// NewThrottle returns a throttle that allows the given number of jobs to start per second across every worker, a rate
+// of zero does not limit the rate. Each worker also waits for the delay plus a random amount of up to the jitter before
+// each job, so that requests are not sent in lockstep.
+func NewThrottle(perSecond float64, delay, jitter time.Duration) *Throttle {
+	limit := rate.Inf
+	if perSecond > 0 {
+		limit = rate.Limit(perSecond)
+	}
+	return &Throttle{
+		// a burst of one spaces the jobs evenly across each second rather than starting a second's worth at once
+		limiter: rate.NewLimiter(limit, 1),
+		limit:   limit,
+		delay:   delay,
+		jitter:  jitter,
+		backoff: minBackoff,
+	}
+}
+
+// Wait blocks until the next job is allowed to start, a nil throttle never blocks
+func (t *Throttle) Wait() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	// a pause that has already expired is not negative time, otherwise it would cancel out the delay
+	paused := max(time.Until(t.pausedUntil), 0)
+	t.mu.Unlock()
+	if pause := paused + t.pause(); pause > 0 {
+		time.Sleep(pause)
+	}
+	_ = t.limiter.Wait(context.Background())
+}
+
+// Observe will slow every worker down if the response shows the target is asking to slow down, returning true so that
+// the job can be run again, otherwise it counts towards ramping the rate back up. A throttle that is not adaptive never
+// slows down.
+func (t *Throttle) Observe(response *client.Response) bool {
+	if t == nil || !t.Adaptive {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable {
+		t.rampUp()
+		return false
+	}
+
+	t.successes = 0
+	now := time.Now()
+	pause := response.RetryAfter
+	if pause == 0 {
+		pause = t.backoff
+	}
+	// every worker that was already running will see the same response, so only slow down once per pause
+	if now.Before(t.pausedUntil) {
+		t.pausedUntil = maxTime(t.pausedUntil, now.Add(pause))
+		return true
+	}
+
+	if response.RetryAfter == 0 {
+		t.backoff = min(t.backoff*2, maxBackoff)
+	}
+	t.pausedUntil = now.Add(pause)
+	limit := rate.Limit(backoffRate)
+	if current := t.limiter.Limit(); current != rate.Inf {
+		// the floor has to stay below the configured rate, otherwise slowing down a rate under one would speed it up
+		limit = min(max(current/2, min(minRate, t.limit/8)), t.limit)
+	}
+	t.limiter.SetLimit(limit)
+	log.Warnf("target responded with %d, pausing for %v and slowing down to %.1f requests per second",
+		response.StatusCode, pause, float64(t.limiter.Limit()))
+	return true
+}
+
+// rampUp will double the rate back towards the limit once enough responses in a row have been fine, must be called
+// whilst holding the lock
+func (t *Throttle) rampUp() {
+	if t.limiter.Limit() >= t.limit {
+		return
+	}
+	t.successes++
+	if t.successes < rampUpAfter {
+		return
+	}
+	t.successes = 0
+	limit := t.limiter.Limit() * 2
+	// without a rate limit there is nothing to ramp up to, so stop limiting once the rate is well above where it started
+	if t.limit == rate.Inf && limit >= backoffRate*8 {
+		limit = rate.Inf
+	}
+	if limit >= t.limit {
+		limit = t.limit
+		t.backoff = minBackoff
+	}
+	t.limiter.SetLimit(limit)
+	log.Debugf("target has recovered, speeding up to %.1f requests per second", float64(limit))
+}
+
+// pause returns how long to wait before a job, the delay plus a random amount of up to the jitter
+func (t *Throttle) pause() time.Duration {
+	pause := t.delay
+	if t.jitter > 0 {
+		pause += time.Duration(rand.Int63n(int64(t.jitter) + 1)) // #nosec G404
+	}
+	return pause
+}
+
+// maxTime returns the later of the two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,140 @@
+package job
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestThrottle_Wait(t *testing.T) {
+	t.Run("should limit the rate jobs are started at", func(t *testing.T) {
+		throttle := NewThrottle(20, 0, 0)
+
+		started := time.Now()
+		for range 6 {
+			throttle.Wait()
+		}
+
+		// the first job starts straight away and each one after is spaced 50ms apart
+		if took := time.Since(started); took < 200*time.Millisecond {
+			t.Errorf("Expected 6 jobs to take at least 200ms, but took %v", took)
+		}
+	})
+
+	t.Run("should wait for the delay before each job", func(t *testing.T) {
+		throttle := NewThrottle(0, 50*time.Millisecond, 0)
+
+		started := time.Now()
+		for range 3 {
+			throttle.Wait()
+		}
+
+		if took := time.Since(started); took < 150*time.Millisecond {
+			t.Errorf("Expected 3 jobs to take at least 150ms, but took %v", took)
+		}
+	})
+
+	t.Run("should still wait for the delay once a pause has expired", func(t *testing.T) {
+		throttle := NewThrottle(0, 50*time.Millisecond, 0)
+		throttle.pausedUntil = time.Now().Add(-time.Hour)
+
+		started := time.Now()
+		throttle.Wait()
+
+		if took := time.Since(started); took < 50*time.Millisecond {
+			t.Errorf("Expected to wait at least 50ms, but took %v", took)
+		}
+	})
+
+	t.Run("should not block without a rate or delay", func(t *testing.T) {
+		for _, throttle := range []*Throttle{nil, NewThrottle(0, 0, 0)} {
+			started := time.Now()
+			for range 100 {
+				throttle.Wait()
+			}
+			if took := time.Since(started); took > 50*time.Millisecond {
+				t.Errorf("Expected no waiting, but took %v", took)
+			}
+		}
+	})
+}
+
+func TestThrottle_pause(t *testing.T) {
+	t.Run("should pause for the delay plus up to the jitter", func(t *testing.T) {
+		throttle := NewThrottle(0, 100*time.Millisecond, 50*time.Millisecond)
+		for range 100 {
+			pause := throttle.pause()
+			if pause < 100*time.Millisecond || pause > 150*time.Millisecond {
+				t.Errorf("Expected pause between 100ms and 150ms, but got %v", pause)
+			}
+		}
+	})
+}
+
+func TestThrottle_Observe(t *testing.T) {
+	t.Run("should slow down when the target responds with 429", func(t *testing.T) {
+		throttle := NewThrottle(0, 0, 0)
+		throttle.Adaptive = true
+
+		if !throttle.Observe(&client.Response{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}) {
+			t.Errorf("Expected the job to be run again")
+		}
+		if throttle.limiter.Limit() != backoffRate {
+			t.Errorf("Expected rate to drop to %v, but got %v", backoffRate, throttle.limiter.Limit())
+		}
+		if paused := time.Until(throttle.pausedUntil); paused < time.Second || paused > 2*time.Second {
+			t.Errorf("Expected to pause for the Retry-After of 2s, but pausing for %v", paused)
+		}
+
+		// a second response within the pause should not slow down any further
+		throttle.Observe(&client.Response{StatusCode: http.StatusServiceUnavailable})
+		if throttle.limiter.Limit() != backoffRate {
+			t.Errorf("Expected rate to stay at %v, but got %v", backoffRate, throttle.limiter.Limit())
+		}
+	})
+
+	t.Run("should halve a configured rate and ramp back up once recovered", func(t *testing.T) {
+		throttle := NewThrottle(20, 0, 0)
+		throttle.Adaptive = true
+
+		throttle.Observe(&client.Response{StatusCode: http.StatusTooManyRequests})
+		if throttle.limiter.Limit() != 10 {
+			t.Errorf("Expected rate to halve to 10, but got %v", throttle.limiter.Limit())
+		}
+
+		for range rampUpAfter {
+			if throttle.Observe(&client.Response{StatusCode: http.StatusOK}) {
+				t.Errorf("Expected a successful job not to be run again")
+			}
+		}
+		if throttle.limiter.Limit() != 20 {
+			t.Errorf("Expected rate to ramp back up to 20, but got %v", throttle.limiter.Limit())
+		}
+	})
+
+	t.Run("should never speed up a rate below one when slowing down", func(t *testing.T) {
+		throttle := NewThrottle(0.5, 0, 0)
+		throttle.Adaptive = true
+
+		throttle.Observe(&client.Response{StatusCode: http.StatusTooManyRequests})
+		if throttle.limiter.Limit() != 0.25 {
+			t.Errorf("Expected rate to halve to 0.25, but got %v", throttle.limiter.Limit())
+		}
+
+		for range rampUpAfter {
+			throttle.Observe(&client.Response{StatusCode: http.StatusOK})
+		}
+		if throttle.limiter.Limit() != 0.5 {
+			t.Errorf("Expected rate to ramp back up to 0.5, but got %v", throttle.limiter.Limit())
+		}
+	})
+
+	t.Run("should not slow down unless adaptive", func(t *testing.T) {
+		throttle := NewThrottle(0, 0, 0)
+		if throttle.Observe(&client.Response{StatusCode: http.StatusTooManyRequests}) {
+			t.Errorf("Expected the job not to be run again")
+		}
+	})
+}
@@ -2,8 +2,6 @@
 package job
 
 import (
-	"sync"
-
 	"github.com/ch55secake/dizzy/pkg/client"
 	"github.com/sirupsen/logrus"
 )
@@ -12,17 +10,24 @@ import (
 type Worker struct {
 	ID         int
 	JobChannel chan *Job
+	Results    chan<- Result
 	Requester  *client.Requester
-	wg         *sync.WaitGroup
+	Throttle   *Throttle
 }
 
-// Start will kick off the processing loop for a given job, will stop when the job has been executed
+// Start will kick off the processing loop for a given job, will stop when the job has been executed, the outcome of
+// each job is published onto the results channel
 func (w *Worker) Start() {
 	go func() {
 		for job := range w.JobChannel {
+			w.Throttle.Wait()
 			logrus.Debugf("Worker %d starting job %d", w.ID, job.ID)
-			job.Execute(w.Requester)
-			w.wg.Done()
+			response, err := job.Execute(w.Requester)
+			w.Results <- Result{
+				Job:      job,
+				Response: response,
+				Err:      err,
+			}
 		}
 	}()
 }
@@ -3,7 +3,6 @@ package job
 import (
 	"net/http"
 	"net/http/httptest"
-	"sync"
 	"testing"
 	"time"
 
@@ -11,7 +10,7 @@ import (
 )
 
 func TestWorker_Start(t *testing.T) {
-	t.Run("should be able to start a worker without errors", func(_ *testing.T) {
+	t.Run("should be able to start a worker without errors", func(t *testing.T) {
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
@@ -23,7 +22,7 @@ func TestWorker_Start(t *testing.T) {
 		defer mockServer.Close()
 
 		jobChannel := make(chan *Job)
-		wg := &sync.WaitGroup{}
+		results := make(chan Result, 1)
 
 		mockRequester := &client.Requester{
 			Timeout: 10 * time.Second,
@@ -33,8 +32,8 @@ func TestWorker_Start(t *testing.T) {
 		worker := &Worker{
 			ID:         1,
 			JobChannel: jobChannel,
+			Results:    results,
 			Requester:  mockRequester,
-			wg:         wg,
 		}
 
 		worker.Start()
@@ -44,10 +43,18 @@ func TestWorker_Start(t *testing.T) {
 		}
 
 		job := NewJob(1, mockRequest)
-		wg.Add(1)
 		jobChannel <- job
 
-		wg.Wait()
+		result := <-results
+		if result.Err != nil {
+			t.Errorf("Expected no error, but got %v", result.Err)
+		}
+		if result.Job != job {
+			t.Errorf("Expected result for job %d, but got %d", job.ID, result.Job.ID)
+		}
+		if result.Response.StatusCode != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, result.Response.StatusCode)
+		}
 
 		close(jobChannel)
 	})
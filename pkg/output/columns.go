@@ -0,0 +1,147 @@
+package output
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/ch55secake/dizzy/pkg/dns"
+)
+
+// Column is a single column of a result, the same columns are used when printing results whilst a scan is running and
+// when writing them out once it has finished
+type Column struct {
+	Name    string
+	Width   int
+	Numeric bool
+	Value   func(response *client.Response) string
+}
+
+// PathColumns are the columns shown when scanning for paths, the path is relative to the target so that results found
+// whilst recursing can be told apart from those with the same word found in the target itself
+var PathColumns = []Column{
+	{Name: "Path", Width: 20, Value: func(response *client.Response) string {
+		if response.Path == "" {
+			return response.Subdomain
+		}
+		return response.Path
+	}},
+	StatusColumn,
+	{Name: "Body Length", Width: 15, Numeric: true, Value: func(response *client.Response) string {
+		return strconv.Itoa(response.BodyLength)
+	}},
+}
+
+// SubdomainColumns are the columns shown when scanning for subdomains
+var SubdomainColumns = []Column{
+	hostColumn,
+	addressColumn,
+	StatusColumn,
+	TitleColumn,
+}
+
+// DNSColumns are the columns shown when resolving subdomains, resolved subdomains that are probed over http also show
+// the status and title columns
+var DNSColumns = []Column{
+	hostColumn,
+	{Name: "A", Width: 20, Value: records(func(r *dns.Records) string { return strings.Join(r.A, ",") })},
+	{Name: "AAAA", Width: 25, Value: records(func(r *dns.Records) string { return strings.Join(r.AAAA, ",") })},
+	{Name: "CNAME", Width: 30, Value: records(func(r *dns.Records) string { return r.CNAME })},
+}
+
+// StatusColumn is the status code of the response
+var StatusColumn = Column{Name: "Status", Width: 10, Numeric: true, Value: func(response *client.Response) string {
+	return strconv.Itoa(response.StatusCode)
+}}
+
+// TitleColumn is the title of the html page in the response
+var TitleColumn = Column{Name: "Title", Width: 40, Value: func(response *client.Response) string {
+	return response.Title
+}}
+
+// PayloadColumn is the word used for each keyword when scanning with more than one wordlist
+var PayloadColumn = Column{Name: "Payload", Width: 40, Value: func(response *client.Response) string {
+	pairs := make([]string, 0, len(response.Payload))
+	for keyword, word := range response.Payload {
+		pairs = append(pairs, keyword+"="+word)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, " ")
+}}
+
+// hostColumn is the host that was requested or resolved
+var hostColumn = Column{Name: "Host", Width: 30, Value: func(response *client.Response) string {
+	return response.Host
+}}
+
+// addressColumn is the address that the host resolved to and was connected to, without the port
+var addressColumn = Column{Name: "Address", Width: 20, Value: func(response *client.Response) string {
+	host, _, err := net.SplitHostPort(response.Address)
+	if err != nil {
+		return response.Address
+	}
+	return host
+}}
+
+// ColumnsFor returns the columns that should be shown for the given mode
+func ColumnsFor(mode client.Mode) []Column {
+	switch mode {
+	case client.ModeSubdomain:
+		return SubdomainColumns
+	case client.ModeDNS:
+		return DNSColumns
+	default:
+		return PathColumns
+	}
+}
+
+// records returns a column value from the dns records of the response, which is empty if the host was not resolved
+func records(value func(r *dns.Records) string) func(response *client.Response) string {
+	return func(response *client.Response) string {
+		if response.DNS == nil {
+			return ""
+		}
+		return value(response.DNS)
+	}
+}
+
+// names returns the name of each column
+func names(columns []Column) []string {
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.Name)
+	}
+	return headers
+}
+
+// values returns the value of each column for the response
+func values(columns []Column, response *client.Response) []string {
+	row := make([]string, 0, len(columns))
+	for _, column := range columns {
+		row = append(row, column.Value(response))
+	}
+	return row
+}
+
+// padded will pad each cell to the width of its column so that rows line up when printed
+func padded(columns []Column, cells []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-3s", ""))
+	for i, column := range columns {
+		sb.WriteString(fmt.Sprintf(" %-*s", column.Width, cells[i]))
+	}
+	return sb.String()
+}
+
+// PrintHeader will print the name of each column
+func PrintHeader(columns []Column) {
+	PrintMagentaMessage(padded(columns, names(columns)), true)
+}
+
+// PrintResult will print the value of each column for the response
+func PrintResult(columns []Column, response *client.Response) {
+	PrintCyanMessage(padded(columns, values(columns, response)), true)
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+	"github.com/fatih/color"
+)
+
+func TestPrintResult(t *testing.T) {
+	t.Run("should print values from the target as is rather than as a format string", func(t *testing.T) {
+		var buf bytes.Buffer
+		output, noColor := color.Output, color.NoColor
+		color.Output, color.NoColor = &buf, true
+		defer func() {
+			color.Output, color.NoColor = output, noColor
+		}()
+
+		response := &client.Response{StatusCode: http.StatusOK, Host: "admin.example.com", Title: "100%s %d off"}
+		PrintResult(SubdomainColumns, response)
+
+		if !strings.Contains(buf.String(), "100%s %d off") || strings.Contains(buf.String(), "%!") {
+			t.Errorf("Expected the title to be printed as is, but got %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+//go:embed templates/report.html
+var templates embed.FS
+
+// reportTemplate is the single file html report, all styling and scripting is inlined so that it can be opened offline
+var reportTemplate = template.Must(template.New("report.html").Funcs(template.FuncMap{
+	"statusClass":  statusClass,
+	"milliseconds": milliseconds,
+	"cell":         cell,
+}).ParseFS(templates, "templates/report.html"))
+
+// histogramBucket is the number of results that returned a given status code
+type histogramBucket struct {
+	StatusCode int
+	Count      int
+	Percent    float64
+}
+
+// Class returns the class of the status code, i.e. 2 for 2xx
+func (b histogramBucket) Class() int {
+	return statusClass(b.StatusCode)
+}
+
+// htmlReport is the data that is passed to the html template, columns default to the path columns if the report does
+// not have any
+type htmlReport struct {
+	*Report
+	Columns   []Column
+	Histogram []histogramBucket
+}
+
+// WriteHTML will write the report as a single self-contained html file, which includes a summary of the scan, a
+// histogram of status codes and a sortable and filterable table of every result
+func WriteHTML(w io.Writer, report *Report) error {
+	err := reportTemplate.Execute(w, htmlReport{
+		Report:    report,
+		Columns:   columnsOrDefault(report.Columns),
+		Histogram: histogram(report),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing html report: %w", err)
+	}
+	return nil
+}
+
+// histogram will count the results by status code, ordered by status code
+func histogram(report *Report) []histogramBucket {
+	counts := make(map[int]int)
+	for _, response := range report.Results {
+		counts[response.StatusCode]++
+	}
+
+	buckets := make([]histogramBucket, 0, len(counts))
+	for statusCode, count := range counts {
+		buckets = append(buckets, histogramBucket{
+			StatusCode: statusCode,
+			Count:      count,
+			Percent:    float64(count) / float64(len(report.Results)) * 100,
+		})
+	}
+	slices.SortFunc(buckets, func(a, b histogramBucket) int {
+		return a.StatusCode - b.StatusCode
+	})
+	return buckets
+}
+
+// statusClass returns the class of the status code, i.e. 2 for 2xx
+func statusClass(statusCode int) int {
+	return statusCode / 100
+}
+
+// columnsOrDefault returns the columns or the path columns if there are none
+func columnsOrDefault(columns []Column) []Column {
+	if len(columns) == 0 {
+		return PathColumns
+	}
+	return columns
+}
+
+// cell returns the value of the column for the response
+func cell(column Column, response client.Response) string {
+	return column.Value(&response)
+}
+
+// milliseconds formats the duration as whole milliseconds
+func milliseconds(d time.Duration) int64 {
+	return d.Milliseconds()
+}
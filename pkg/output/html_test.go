@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestWriteHTML(t *testing.T) {
+	t.Run("should write a report containing the summary, histogram and every result", func(t *testing.T) {
+		started := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		report := &Report{
+			Target:   "http://example.com",
+			Wordlist: "/path/to/wordlist.txt",
+			Method:   http.MethodGet,
+			Headers:  map[string]string{"Accept": "application/json"},
+			Started:  started,
+			Finished: started.Add(2 * time.Second),
+			Results: []client.Response{
+				{StatusCode: http.StatusOK, BodyLength: 22, Subdomain: "admin"},
+				{StatusCode: http.StatusNotFound, BodyLength: 0, Subdomain: "<script>alert(1)</script>"},
+				{StatusCode: http.StatusOK, BodyLength: 10, Subdomain: "login"},
+			},
+		}
+
+		var buf bytes.Buffer
+		err := WriteHTML(&buf, report)
+		if err != nil {
+			t.Fatalf("WriteHTML returned an unexpected error: %v", err)
+		}
+
+		html := buf.String()
+		for _, expected := range []string{
+			"http://example.com",
+			"/path/to/wordlist.txt",
+			"Accept: application/json",
+			"2024-01-02 15:04:05 UTC",
+			"2s",
+			"<td>admin</td>",
+			"<td>login</td>",
+			"&lt;script&gt;alert(1)&lt;/script&gt;",
+		} {
+			if !strings.Contains(html, expected) {
+				t.Errorf("Expected report to contain %q", expected)
+			}
+		}
+
+		if strings.Contains(html, "<script>alert(1)</script>") {
+			t.Errorf("Expected results to be escaped in the report")
+		}
+	})
+}
+
+func Test_histogram(t *testing.T) {
+	t.Run("should count results by status code in order", func(t *testing.T) {
+		report := &Report{
+			Results: []client.Response{
+				{StatusCode: http.StatusNotFound},
+				{StatusCode: http.StatusOK},
+				{StatusCode: http.StatusNotFound},
+				{StatusCode: http.StatusNotFound},
+			},
+		}
+
+		buckets := histogram(report)
+
+		expected := []histogramBucket{
+			{StatusCode: http.StatusOK, Count: 1, Percent: 25},
+			{StatusCode: http.StatusNotFound, Count: 3, Percent: 75},
+		}
+		if len(buckets) != len(expected) {
+			t.Fatalf("Expected %d buckets, got %d", len(expected), len(buckets))
+		}
+		for i, bucket := range buckets {
+			if bucket != expected[i] {
+				t.Errorf("bucket %d: got %+v, want %+v", i, bucket, expected[i])
+			}
+		}
+	})
+}
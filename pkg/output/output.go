@@ -20,12 +20,12 @@ func DefaultMessage() {
 func PrintCyanMessage(message string, square bool) {
 	cyan := color.New(color.FgCyan, color.Bold)
 	if square {
-		_, err := cyan.Printf("[+] " + message + "\n")
+		_, err := cyan.Printf("[+] %s\n", message)
 		if err != nil {
 			return
 		}
 	} else {
-		_, err := cyan.Printf(message + "\n")
+		_, err := cyan.Printf("%s\n", message)
 		if err != nil {
 			return
 		}
@@ -35,12 +35,12 @@ func PrintCyanMessage(message string, square bool) {
 func PrintMagentaMessage(message string, square bool) {
 	magenta := color.New(color.FgMagenta, color.Bold)
 	if square {
-		_, err := magenta.Printf("[+] " + message + "\n")
+		_, err := magenta.Printf("[+] %s\n", message)
 		if err != nil {
 			return
 		}
 	} else {
-		_, err := magenta.Printf(message + "\n")
+		_, err := magenta.Printf("%s\n", message)
 		if err != nil {
 			return
 		}
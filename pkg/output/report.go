@@ -0,0 +1,24 @@
+package output
+
+import (
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// Report describes a finished scan, what was scanned and the results that were collected
+type Report struct {
+	Target   string
+	Wordlist string
+	Method   string
+	Headers  map[string]string
+	Started  time.Time
+	Finished time.Time
+	Columns  []Column
+	Results  []client.Response
+}
+
+// Duration returns how long the scan took to run
+func (r *Report) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+// Format is the format that results will be written out in
+type Format string
+
+const (
+	// FormatJSON writes each response as its own line of json
+	FormatJSON Format = "json"
+	// FormatCSV writes the responses as comma separated values with a header row
+	FormatCSV Format = "csv"
+	// FormatMarkdown writes the responses as a markdown table
+	FormatMarkdown Format = "markdown"
+	// FormatHTML writes a self-contained html report of the scan
+	FormatHTML Format = "html"
+)
+
+// ParseFormat will return the format matching the given name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatJSON, FormatCSV, FormatMarkdown, FormatHTML:
+		return format, nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s", name)
+	}
+}
+
+// WriteReport will write the report to the given writer in the given format
+func WriteReport(w io.Writer, format Format, report *Report) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSONLines(w, report.Results)
+	case FormatCSV:
+		return WriteCSV(w, columnsOrDefault(report.Columns), report.Results)
+	case FormatMarkdown:
+		return WriteMarkdown(w, columnsOrDefault(report.Columns), report.Results)
+	case FormatHTML:
+		return WriteHTML(w, report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// WriteJSONLines will write each response as its own line of json to the given writer, so that the output can be piped
+// into tools like jq
+func WriteJSONLines(w io.Writer, responses []client.Response) error {
+	encoder := json.NewEncoder(w)
+	for _, response := range responses {
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("error encoding response for %s: %w", response.URL, err)
+		}
+	}
+	return nil
+}
+
+// WriteCSV will write the given columns of the responses as csv with a header row, so that they can be opened in a
+// spreadsheet
+func WriteCSV(w io.Writer, columns []Column, responses []client.Response) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(names(columns)); err != nil {
+		return fmt.Errorf("error writing csv header: %w", err)
+	}
+
+	for i := range responses {
+		if err := writer.Write(values(columns, &responses[i])); err != nil {
+			return fmt.Errorf("error writing csv record for %s: %w", responses[i].URL, err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteMarkdown will write the given columns of the responses as a markdown table, so that they can be dropped straight
+// into a report
+func WriteMarkdown(w io.Writer, columns []Column, responses []client.Response) error {
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(names(columns), " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for i := range responses {
+		cells := values(columns, &responses[i])
+		for j, cell := range cells {
+			cells[j] = escapeMarkdown(cell)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return fmt.Errorf("error writing markdown table: %w", err)
+	}
+	return nil
+}
+
+// escapeMarkdown will escape any characters in the cell that would otherwise break the table
+func escapeMarkdown(cell string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(cell)
+}
+
+// WriteFile will create the file at the given path, truncating it if it already exists, and write the report to it in
+// the given format
+func WriteFile(filepath string, format Format, report *Report) error {
+	file, err := os.Create(filepath) // #nosec G304
+	if err != nil {
+		return fmt.Errorf("error creating output file: %w", err)
+	}
+
+	if err := WriteReport(file, format, report); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing output file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ch55secake/dizzy/pkg/client"
+)
+
+func TestWriteJSONLines(t *testing.T) {
+	t.Run("should write each response as its own line of json", func(t *testing.T) {
+		responses := []client.Response{
+			{
+				StatusCode: http.StatusOK,
+				BodyLength: 22,
+				Subdomain:  "admin",
+				URL:        "http://example.com/admin",
+				Method:     http.MethodGet,
+				Duration:   10 * time.Millisecond,
+			},
+			{
+				StatusCode: 408,
+				Subdomain:  "slow",
+				URL:        "http://example.com/slow",
+				Method:     http.MethodGet,
+				Error:      "context deadline exceeded",
+			},
+		}
+
+		var buf bytes.Buffer
+		err := WriteJSONLines(&buf, responses)
+		if err != nil {
+			t.Fatalf("WriteJSONLines returned an unexpected error: %v", err)
+		}
+
+		scanner := bufio.NewScanner(&buf)
+		var lines int
+		for scanner.Scan() {
+			var got client.Response
+			if err := json.Unmarshal(scanner.Bytes(), &got); err != nil {
+				t.Fatalf("line %d is not valid json: %v", lines, err)
+			}
+			if !reflect.DeepEqual(got, responses[lines]) {
+				t.Errorf("line %d: got %+v, want %+v", lines, got, responses[lines])
+			}
+			lines++
+		}
+
+		if lines != len(responses) {
+			t.Errorf("Expected %d lines, got %d", len(responses), lines)
+		}
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Run("should write a header row and a record for each response", func(t *testing.T) {
+		responses := []client.Response{
+			{StatusCode: http.StatusOK, BodyLength: 22, Subdomain: "admin"},
+			{StatusCode: http.StatusNotFound, BodyLength: 0, Subdomain: "a,b"},
+			{StatusCode: http.StatusOK, BodyLength: 5, Subdomain: "admin", Path: "admin/admin"},
+		}
+
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, PathColumns, responses)
+		if err != nil {
+			t.Fatalf("WriteCSV returned an unexpected error: %v", err)
+		}
+
+		expected := "Path,Status,Body Length\nadmin,200,22\n\"a,b\",404,0\nadmin/admin,200,5\n"
+		if buf.String() != expected {
+			t.Errorf("WriteCSV() = %q; want %q", buf.String(), expected)
+		}
+	})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	t.Run("should write a markdown table with a row for each response", func(t *testing.T) {
+		responses := []client.Response{
+			{StatusCode: http.StatusOK, BodyLength: 22, Subdomain: "admin"},
+			{StatusCode: http.StatusForbidden, BodyLength: 7, Subdomain: "a|b"},
+		}
+
+		var buf bytes.Buffer
+		err := WriteMarkdown(&buf, PathColumns, responses)
+		if err != nil {
+			t.Fatalf("WriteMarkdown returned an unexpected error: %v", err)
+		}
+
+		expected := "| Path | Status | Body Length |\n| --- | --- | --- |\n| admin | 200 | 22 |\n| a\\|b | 403 | 7 |\n"
+		if buf.String() != expected {
+			t.Errorf("WriteMarkdown() = %q; want %q", buf.String(), expected)
+		}
+	})
+}
+
+func TestWriteCSV_SubdomainColumns(t *testing.T) {
+	t.Run("should write the subdomain columns", func(t *testing.T) {
+		responses := []client.Response{
+			{StatusCode: http.StatusOK, Host: "admin.example.com", Address: "93.184.216.34:443", Title: "Admin"},
+		}
+
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, ColumnsFor(client.ModeSubdomain), responses)
+		if err != nil {
+			t.Fatalf("WriteCSV returned an unexpected error: %v", err)
+		}
+
+		expected := "Host,Address,Status,Title\nadmin.example.com,93.184.216.34,200,Admin\n"
+		if buf.String() != expected {
+			t.Errorf("WriteCSV() = %q; want %q", buf.String(), expected)
+		}
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      Format
+		wantError bool
+	}{
+		{name: "should parse json", input: "json", want: FormatJSON},
+		{name: "should parse csv regardless of case", input: "CSV", want: FormatCSV},
+		{name: "should parse md as markdown", input: "md", want: FormatMarkdown},
+		{name: "should parse html", input: "html", want: FormatHTML},
+		{name: "should return an error for an unknown format", input: "xml", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error, got format: %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}